@@ -0,0 +1,313 @@
+// analyzer checks go-inject modules statically, reporting problems that the injector would
+// only report when it is created, or not at all.
+//
+// Static modules are found as the package's named types with exported `Provide*` methods,
+// except dynamic modules, that have a `Providers` method, and the auto package's
+// `ProvideAutoInjectAnnotations` method. The analyzer reports:
+//   - provider methods with signatures that the injector rejects;
+//   - other exported methods of modules, which make the injector reject the whole module;
+//   - annotation types that are not empty structs: annotations only identify keys by their types
+//     and are passed to providers as zero values, so fields and pointers are never set;
+//   - cached providers that modify their pointer receivers: they are called once per injector,
+//     so modifications of the module depend on which values were provided first;
+//   - unexported annotation types of provided values that nothing in the package depends on,
+//     so that the values can never be provided.
+//
+// The analyzer can be run with `go vet -vettool` using the inject-vet command.
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const providerPrefix = "Provide"
+const cachedProviderPrefix = providerPrefix + "Cached"
+
+// The method of structs injected by the auto package, which are not modules.
+const autoInjectAnnotationsMethod = "ProvideAutoInjectAnnotations"
+
+// The analyzer of go-inject modules.
+var Analyzer = &analysis.Analyzer{
+	Name: "inject",
+	Doc:  "check go-inject modules: provider signatures, module methods and annotation types",
+	Run:  run,
+}
+
+// A provider method declaration of a module with its checked signature.
+type providerDeclaration struct {
+	declaration *ast.FuncDecl
+	// The output annotation type expression, nil if the signature is invalid.
+	annotation ast.Expr
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	providers := []providerDeclaration{}
+	for _, file := range pass.Files {
+		for _, declaration := range file.Decls {
+			method, ok := declaration.(*ast.FuncDecl)
+			if !ok || method.Recv == nil || !isModule(receiverType(pass, method)) {
+				continue
+			}
+			if !method.Name.IsExported() || method.Name.Name == autoInjectAnnotationsMethod {
+				continue
+			}
+			if !strings.HasPrefix(method.Name.Name, providerPrefix) {
+				pass.Reportf(method.Name.Pos(),
+					"method %s of module %s is not a provider: the injector rejects modules with "+
+						"exported methods without the %q prefix",
+					method.Name.Name, receiverType(pass, method).Obj().Name(), providerPrefix)
+				continue
+			}
+			providers = append(providers, checkProvider(pass, method))
+		}
+	}
+	checkUnconsumedAnnotations(pass, providers)
+	return nil, nil
+}
+
+// Get the named type of the method's receiver.
+func receiverType(pass *analysis.Pass, method *ast.FuncDecl) *types.Named {
+	receiver := pass.TypesInfo.TypeOf(method.Recv.List[0].Type)
+	if pointer, ok := receiver.(*types.Pointer); ok {
+		receiver = pointer.Elem()
+	}
+	named, _ := receiver.(*types.Named)
+	return named
+}
+
+// Test if the type is a static module: it has exported provider methods and
+// it is not a dynamic module.
+func isModule(moduleType *types.Named) bool {
+	if moduleType == nil {
+		return false
+	}
+	methods := types.NewMethodSet(types.NewPointer(moduleType))
+	if methods.Lookup(moduleType.Obj().Pkg(), "Providers") != nil {
+		return false
+	}
+	for index := 0; index < methods.Len(); index += 1 {
+		method := methods.At(index).Obj()
+		if method.Exported() && strings.HasPrefix(method.Name(), providerPrefix) &&
+			method.Name() != autoInjectAnnotationsMethod {
+			return true
+		}
+	}
+	return false
+}
+
+// Check the signature of the provider, its annotation types and its receiver.
+func checkProvider(pass *analysis.Pass, method *ast.FuncDecl) providerDeclaration {
+	result := providerDeclaration{declaration: method}
+	signature := pass.TypesInfo.Defs[method.Name].Type().(*types.Signature)
+	results := signature.Results()
+	if results.Len() != 2 && results.Len() != 3 {
+		pass.Reportf(method.Name.Pos(),
+			"provider %s should return a value and an annotation and optionally an error, returns %d values",
+			method.Name.Name, results.Len())
+		return result
+	}
+	if results.Len() == 3 && !isError(results.At(2).Type()) {
+		pass.Reportf(method.Name.Pos(),
+			"provider %s should return an error as its third value, returns %v",
+			method.Name.Name, results.At(2).Type())
+		return result
+	}
+
+	params := signature.Params()
+	firstInput := 0
+	if params.Len()%2 == 1 && isContext(params.At(0).Type()) {
+		firstInput = 1
+	}
+	if (params.Len()-firstInput)%2 != 0 || signature.Variadic() {
+		pass.Reportf(method.Name.Pos(),
+			"provider %s should take pairs of values and annotations, optionally after a context",
+			method.Name.Name)
+		return result
+	}
+
+	resultTypes := fieldTypes(method.Type.Results)
+	result.annotation = resultTypes[1]
+	checkAnnotation(pass, resultTypes[1])
+	paramTypes := fieldTypes(method.Type.Params)
+	for index := firstInput + 1; index < len(paramTypes); index += 2 {
+		checkAnnotation(pass, paramTypes[index])
+	}
+
+	if strings.HasPrefix(method.Name.Name, cachedProviderPrefix) {
+		checkReceiverNotModified(pass, method)
+	}
+	return result
+}
+
+// Get type expressions of the list's fields, one for every name.
+func fieldTypes(fields *ast.FieldList) []ast.Expr {
+	expressions := []ast.Expr{}
+	if fields == nil {
+		return expressions
+	}
+	for _, field := range fields.List {
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for index := 0; index < count; index += 1 {
+			expressions = append(expressions, field.Type)
+		}
+	}
+	return expressions
+}
+
+func isError(valueType types.Type) bool {
+	errorType := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	return types.Implements(valueType, errorType)
+}
+
+func isContext(valueType types.Type) bool {
+	named, ok := valueType.(*types.Named)
+	return ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// Check that the annotation type is an empty struct.
+func checkAnnotation(pass *analysis.Pass, expression ast.Expr) {
+	annotationType := pass.TypesInfo.TypeOf(expression)
+	if _, ok := annotationType.(*types.Pointer); ok {
+		pass.Reportf(expression.Pos(),
+			"annotation type %v is a pointer: annotations are passed as zero values, use an empty struct",
+			annotationType)
+		return
+	}
+	structType, ok := annotationType.Underlying().(*types.Struct)
+	if !ok || structType.NumFields() != 0 {
+		pass.Reportf(expression.Pos(),
+			"annotation type %v is not an empty struct: annotations are passed as zero values",
+			annotationType)
+	}
+}
+
+// Check that the cached provider with a pointer receiver does not modify it.
+func checkReceiverNotModified(pass *analysis.Pass, method *ast.FuncDecl) {
+	receiverField := method.Recv.List[0]
+	if _, ok := receiverField.Type.(*ast.StarExpr); !ok || len(receiverField.Names) == 0 || method.Body == nil {
+		return
+	}
+	receiver := pass.TypesInfo.Defs[receiverField.Names[0]]
+	if receiver == nil {
+		return
+	}
+
+	report := func(expression ast.Expr) {
+		if modifiesReceiver(pass, expression, receiver) {
+			pass.Reportf(expression.Pos(),
+				"cached provider %s modifies its receiver: it is called once per injector, "+
+					"so the module's state depends on the order in which values are provided",
+				method.Name.Name)
+		}
+	}
+	ast.Inspect(method.Body, func(node ast.Node) bool {
+		switch statement := node.(type) {
+		case *ast.AssignStmt:
+			if statement.Tok == token.DEFINE {
+				return true
+			}
+			for _, expression := range statement.Lhs {
+				report(expression)
+			}
+		case *ast.IncDecStmt:
+			report(statement.X)
+		}
+		return true
+	})
+}
+
+// Test if assigning to the expression modifies the receiver: it is a field, an element or
+// the value of the receiver.
+func modifiesReceiver(pass *analysis.Pass, expression ast.Expr, receiver types.Object) bool {
+	indirect := false
+	for {
+		switch current := expression.(type) {
+		case *ast.Ident:
+			return indirect && pass.TypesInfo.Uses[current] == receiver
+		case *ast.SelectorExpr:
+			expression = current.X
+		case *ast.IndexExpr:
+			expression = current.X
+		case *ast.StarExpr:
+			expression = current.X
+		case *ast.ParenExpr:
+			expression = current.X
+			continue
+		default:
+			return false
+		}
+		indirect = true
+	}
+}
+
+// Check that unexported annotation types of provided values are used somewhere in the package
+// other than by their providers.
+func checkUnconsumedAnnotations(pass *analysis.Pass, providers []providerDeclaration) {
+	// Ranges of the providers' results and return statements, where annotations are produced.
+	type producingRange struct {
+		start token.Pos
+		end   token.Pos
+	}
+	producing := []producingRange{}
+	for _, provider := range providers {
+		if provider.annotation == nil {
+			continue
+		}
+		declaration := provider.declaration
+		producing = append(producing, producingRange{
+			start: declaration.Type.Results.Pos(),
+			end:   declaration.Type.Results.End(),
+		})
+		if declaration.Body == nil {
+			continue
+		}
+		ast.Inspect(declaration.Body, func(node ast.Node) bool {
+			if _, ok := node.(*ast.FuncLit); ok {
+				return false
+			}
+			if statement, ok := node.(*ast.ReturnStmt); ok {
+				producing = append(producing, producingRange{start: statement.Pos(), end: statement.End()})
+			}
+			return true
+		})
+	}
+
+	consumed := map[types.Object]bool{}
+	for identifier, object := range pass.TypesInfo.Uses {
+		if _, ok := object.(*types.TypeName); !ok {
+			continue
+		}
+		isProduced := false
+		for _, producingRange := range producing {
+			if identifier.Pos() >= producingRange.start && identifier.End() <= producingRange.end {
+				isProduced = true
+				break
+			}
+		}
+		if !isProduced {
+			consumed[object] = true
+		}
+	}
+
+	for _, provider := range providers {
+		if provider.annotation == nil {
+			continue
+		}
+		named, ok := pass.TypesInfo.TypeOf(provider.annotation).(*types.Named)
+		if !ok || named.Obj().Pkg() != pass.Pkg || named.Obj().Exported() || consumed[named.Obj()] {
+			continue
+		}
+		pass.Reportf(provider.annotation.Pos(),
+			"annotation type %s is provided by %s, but nothing in the package depends on it",
+			named.Obj().Name(), provider.declaration.Name.Name)
+	}
+}
@@ -0,0 +1,106 @@
+package modules
+
+import (
+	"context"
+)
+
+type annotation struct{}
+type consumedAnnotation struct{}
+type unconsumedAnnotation struct{}
+type pointerAnnotation struct{}
+type fieldAnnotation struct {
+	value int
+}
+
+type Exported struct{}
+
+type Module struct {
+	calls  int
+	values map[string]int
+}
+
+func (self Module) ProvideValue() (int, annotation) {
+	return 0, annotation{}
+}
+
+func (self Module) ProvideContext(ctx context.Context, value int, _ annotation) (string, consumedAnnotation) {
+	return "", consumedAnnotation{}
+}
+
+func (self Module) ProvideExported(value string, _ consumedAnnotation) (string, Exported) {
+	return value, Exported{}
+}
+
+func (self Module) ProvideError() (bool, Exported, error) {
+	return false, Exported{}, nil
+}
+
+func (self Module) ProvideUnconsumed() (int, unconsumedAnnotation) { // want `annotation type unconsumedAnnotation is provided by ProvideUnconsumed, but nothing in the package depends on it`
+	return 0, unconsumedAnnotation{}
+}
+
+func (self Module) ProvideNoAnnotation() int { // want `provider ProvideNoAnnotation should return a value and an annotation and optionally an error, returns 1 values`
+	return 0
+}
+
+func (self Module) ProvideNotError() (int, Exported, string) { // want `provider ProvideNotError should return an error as its third value, returns string`
+	return 0, Exported{}, ""
+}
+
+func (self Module) ProvideOddArguments(value int) (uint, Exported) { // want `provider ProvideOddArguments should take pairs of values and annotations, optionally after a context`
+	return 0, Exported{}
+}
+
+func (self Module) ProvidePointerAnnotation(
+	value int, _ *pointerAnnotation, // want `annotation type \*modules.pointerAnnotation is a pointer: annotations are passed as zero values, use an empty struct`
+) (int8, Exported) {
+	return 0, Exported{}
+}
+
+func (self Module) ProvideFieldAnnotation() (int16, fieldAnnotation) { // want `annotation type modules.fieldAnnotation is not an empty struct: annotations are passed as zero values` `annotation type fieldAnnotation is provided by ProvideFieldAnnotation, but nothing in the package depends on it`
+	return 0, fieldAnnotation{}
+}
+
+func (self Module) Value() int { // want `method Value of module Module is not a provider: the injector rejects modules with exported methods without the "Provide" prefix`
+	return 0
+}
+
+func (self Module) helper() int {
+	return 0
+}
+
+func (self *Module) ProvideCachedCounter() (int32, Exported) {
+	self.calls++                        // want `cached provider ProvideCachedCounter modifies its receiver`
+	self.values["counter"] = self.calls // want `cached provider ProvideCachedCounter modifies its receiver`
+	self = &Module{}
+	return int32(self.calls), Exported{}
+}
+
+func (self *Module) ProvideCounter() (int64, Exported) {
+	self.calls++
+	return int64(self.calls), Exported{}
+}
+
+type DynamicModule struct{}
+
+func (self DynamicModule) Providers() ([]interface{}, error) {
+	return nil, nil
+}
+
+func (self DynamicModule) Value() int {
+	return 0
+}
+
+type NotModule struct{}
+
+func (self NotModule) Value() int {
+	return 0
+}
+
+type AutoInjectable struct {
+	Value int
+}
+
+func (self AutoInjectable) ProvideAutoInjectAnnotations() interface{} {
+	return struct{}{}
+}
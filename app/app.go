@@ -0,0 +1,205 @@
+// app runs applications built from go-inject modules: it creates an injector, provides the values
+// the application needs, starts the injector, waits for a termination signal and shuts it down.
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/monnoroch/go-inject"
+)
+
+// Exit codes of `Application.Main`.
+const (
+	// The application was shut down without errors.
+	ExitOk = 0
+	// The application failed to start.
+	ExitStartFailed = 1
+	// The application started, but failed to shut down cleanly.
+	ExitShutdownFailed = 2
+)
+
+// The default maximum duration of shutting the application down.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// An application: a collection of modules and the values that it needs.
+type Application struct {
+	modules         []inject.Module
+	options         inject.Options
+	roots           []root
+	shutdownTimeout time.Duration
+	signals         []os.Signal
+}
+
+type root struct {
+	pointerToType interface{}
+	annotation    inject.Annotation
+}
+
+// Create an application from the list of modules.
+// By default it has no root values, waits for SIGINT or SIGTERM and has `DefaultShutdownTimeout`.
+func ApplicationOf(modules ...inject.Module) Application {
+	return Application{
+		modules:         modules,
+		roots:           []root{},
+		shutdownTimeout: DefaultShutdownTimeout,
+		signals:         []os.Signal{os.Interrupt, syscall.SIGTERM},
+	}
+}
+
+// Add a root value: a value that is provided before the application starts.
+// Providing root values provides all their dependencies, which register lifecycle hooks to run
+// when the application starts.
+func (self Application) WithRoot(pointerToType interface{}, annotation inject.Annotation) Application {
+	// Limit the capacity so that applications derived from the same one do not share roots.
+	self.roots = append(self.roots[:len(self.roots):len(self.roots)], root{
+		pointerToType: pointerToType,
+		annotation:    annotation,
+	})
+	return self
+}
+
+// Set options of the application's injector.
+func (self Application) WithOptions(options inject.Options) Application {
+	self.options = options
+	return self
+}
+
+// Set the maximum duration of shutting the application down.
+func (self Application) WithShutdownTimeout(timeout time.Duration) Application {
+	self.shutdownTimeout = timeout
+	return self
+}
+
+// Set signals that make the application shut down.
+// Without signals, the application only shuts down when the context of `Run` is done.
+func (self Application) WithSignals(signals ...os.Signal) Application {
+	self.signals = signals
+	return self
+}
+
+// Run the application: create the injector, provide root values and start the injector, then
+// wait until the process receives one of the signals or the context is done and shut down:
+// stop and close the injector.
+// Errors are of type `StartError` if the application failed to start and `ShutdownError` if it failed
+// to shut down.
+func (self Application) Run(ctx context.Context) error {
+	// Listen to signals before starting, so that signals received while starting are not lost.
+	// Notifying without signals would relay all of them.
+	signals := make(chan os.Signal, 1)
+	if len(self.signals) > 0 {
+		signal.Notify(signals, self.signals...)
+		defer signal.Stop(signals)
+	}
+
+	injector, err := inject.InjectorWithOptions(self.options, self.modules...)
+	if err != nil {
+		return StartError{Cause: err}
+	}
+
+	for _, root := range self.roots {
+		if _, err := injector.GetContext(ctx, root.pointerToType, root.annotation); err != nil {
+			return self.shutdownAfterStartError(injector, err)
+		}
+	}
+	if err := injector.Start(ctx); err != nil {
+		return self.shutdownAfterStartError(injector, err)
+	}
+
+	select {
+	case <-signals:
+	case <-ctx.Done():
+	}
+
+	if err := self.shutdown(injector, true); err != nil {
+		return ShutdownError{Cause: err}
+	}
+	return nil
+}
+
+// Run the application with `Run` and exit the process with one of the exit codes.
+// Errors are printed to the standard error.
+func (self Application) Main() {
+	err := self.Run(context.Background())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(ExitCode(err))
+}
+
+// Get an exit code for an error returned by `Application.Run`.
+func ExitCode(err error) int {
+	switch err.(type) {
+	case nil:
+		return ExitOk
+	case ShutdownError:
+		return ExitShutdownFailed
+	default:
+		return ExitStartFailed
+	}
+}
+
+// Close the injector that failed to start.
+// Hooks are not stopped: the injector stops the hooks that it started when it fails to start.
+func (self Application) shutdownAfterStartError(injector *inject.Injector, err error) error {
+	if shutdownErr := self.shutdown(injector, false); shutdownErr != nil {
+		return StartError{Cause: err, ShutdownCause: shutdownErr}
+	}
+	return StartError{Cause: err}
+}
+
+func (self Application) shutdown(injector *inject.Injector, stop bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), self.shutdownTimeout)
+	defer cancel()
+
+	var stopErr error
+	if stop {
+		stopErr = injector.Stop(ctx)
+	}
+	closeErr := injector.Close(ctx)
+	switch {
+	case stopErr == nil:
+		return closeErr
+	case closeErr == nil:
+		return stopErr
+	default:
+		return fmt.Errorf("%s\n%s", stopErr.Error(), closeErr.Error())
+	}
+}
+
+// An error for an application that failed to start.
+type StartError struct {
+	// The reason the application failed to start.
+	Cause error
+	// The error of shutting down the partially started application, if any.
+	ShutdownCause error
+}
+
+func (self StartError) Error() string {
+	if self.ShutdownCause != nil {
+		return fmt.Sprintf("application failed to start: %s\nand to shut down: %s",
+			self.Cause.Error(), self.ShutdownCause.Error())
+	}
+	return fmt.Sprintf("application failed to start: %s", self.Cause.Error())
+}
+
+func (self StartError) Unwrap() error {
+	return self.Cause
+}
+
+// An error for an application that failed to shut down.
+type ShutdownError struct {
+	Cause error
+}
+
+func (self ShutdownError) Error() string {
+	return fmt.Sprintf("application failed to shut down: %s", self.Cause.Error())
+}
+
+func (self ShutdownError) Unwrap() error {
+	return self.Cause
+}
@@ -0,0 +1,175 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/monnoroch/go-inject"
+)
+
+type ApplicationTests struct {
+	suite.Suite
+}
+
+type testAnnotation struct{}
+type testMissingAnnotation struct{}
+
+type testCloser struct {
+	events *[]string
+}
+
+func (self testCloser) Close() error {
+	*self.events = append(*self.events, "close")
+	return nil
+}
+
+type testModule struct {
+	events   *[]string
+	onStart  func() error
+	stopErr  error
+	provided *bool
+}
+
+func (self testModule) ProvideCachedValue(
+	lifecycle *inject.Lifecycle, _ inject.Builtin,
+) (testCloser, testAnnotation) {
+	*self.provided = true
+	lifecycle.Append(inject.Hook{
+		OnStart: func(_ context.Context) error {
+			*self.events = append(*self.events, "start")
+			return self.onStart()
+		},
+		OnStop: func(_ context.Context) error {
+			*self.events = append(*self.events, "stop")
+			return self.stopErr
+		},
+	})
+	return testCloser{events: self.events}, testAnnotation{}
+}
+
+func (self *ApplicationTests) module(onStart func() error) testModule {
+	return testModule{events: &[]string{}, onStart: onStart, provided: new(bool)}
+}
+
+func (self *ApplicationTests) TestRun() {
+	module := self.module(sendSignal)
+	err := self.application(module).Run(context.Background())
+	self.Require().Nil(err)
+	self.Equal([]string{"start", "stop", "close"}, *module.events)
+	self.Equal(ExitOk, ExitCode(err))
+}
+
+func (self *ApplicationTests) TestRunCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	module := self.module(sendSignal)
+	self.Nil(ApplicationOf(module).Run(ctx))
+	self.False(*module.provided)
+}
+
+func (self *ApplicationTests) TestRunWithoutSignals() {
+	// Catch the signal in the test, so that it does not kill the process.
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, syscall.SIGUSR1)
+	defer signal.Stop(received)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	module := self.module(sendSignal)
+	done := make(chan error)
+	go func() {
+		done <- self.application(module).WithSignals().Run(ctx)
+	}()
+	<-received
+	select {
+	case <-done:
+		self.Fail("The application shut down on a signal")
+		return
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	self.Nil(<-done)
+	self.Equal([]string{"start", "stop", "close"}, *module.events)
+}
+
+func (self *ApplicationTests) TestInvalidModule() {
+	err := ApplicationOf(testInvalidModule{}).Run(context.Background())
+	self.Require().NotNil(err)
+	self.IsType(StartError{}, err)
+	self.Equal(ExitStartFailed, ExitCode(err))
+}
+
+type testInvalidModule struct{}
+
+func (self testInvalidModule) Provide() {}
+
+func (self *ApplicationTests) TestRootError() {
+	module := self.module(func() error { return nil })
+	err := self.application(module).
+		WithRoot(new(int), testMissingAnnotation{}).
+		Run(context.Background())
+	self.Require().NotNil(err)
+	self.IsType(StartError{}, err)
+	self.Equal(ExitStartFailed, ExitCode(err))
+	self.Equal([]string{"close"}, *module.events)
+}
+
+type testPanickingModule struct{}
+
+func (self testPanickingModule) ProvidePanicking() (testCloser, testMissingAnnotation) {
+	panic("test panic")
+}
+
+func (self *ApplicationTests) TestRecoverPanics() {
+	err := ApplicationOf(testPanickingModule{}).
+		WithRoot(new(testCloser), testMissingAnnotation{}).
+		WithOptions(inject.Options{RecoverPanics: true}).
+		Run(context.Background())
+	self.Require().NotNil(err)
+	panicErr := inject.ProviderPanicError{}
+	self.True(errors.As(err, &panicErr))
+	self.Equal("test panic", panicErr.Value)
+}
+
+func (self *ApplicationTests) TestStartError() {
+	startError := errors.New("start error")
+	module := self.module(func() error { return startError })
+	err := self.application(module).Run(context.Background())
+	self.Require().NotNil(err)
+	self.IsType(StartError{}, err)
+	self.Equal(ExitStartFailed, ExitCode(err))
+	self.Equal([]string{"start", "close"}, *module.events)
+}
+
+func (self *ApplicationTests) TestShutdownError() {
+	module := self.module(sendSignal)
+	module.stopErr = errors.New("stop error")
+	err := self.application(module).Run(context.Background())
+	self.Require().NotNil(err)
+	self.IsType(ShutdownError{}, err)
+	self.Equal(ExitShutdownFailed, ExitCode(err))
+	self.Equal([]string{"start", "stop", "close"}, *module.events)
+}
+
+func (self *ApplicationTests) application(module testModule) Application {
+	return ApplicationOf(module).
+		WithRoot(new(testCloser), testAnnotation{}).
+		WithSignals(syscall.SIGUSR1)
+}
+
+// Make the application under test shut down.
+func sendSignal() error {
+	return syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+}
+
+func TestApplication(t *testing.T) {
+	suite.Run(t, new(ApplicationTests))
+}
@@ -1,5 +1,5 @@
-/// autoinject extends go-inject library with a way to automatically generate a provider
-/// for a struct type.
+// autoinject extends go-inject library with a way to automatically generate a provider
+// for a struct type.
 package autoinject
 
 import (
@@ -9,15 +9,15 @@ import (
 	"github.com/monnoroch/go-inject"
 )
 
-/// Default annotation for auto-injected types.
-/// Can be used if the program does not have two components of the same type.
+// Default annotation for auto-injected types.
+// Can be used if the program does not have two components of the same type.
 type Auto struct{}
 
-/// An interface to be implemented to support auto-injecting a type.
+// An interface to be implemented to support auto-injecting a type.
 type AutoInjectable interface {
-	/// Returns a mapping of field names to annotations.
-	/// Omitted fields imply `autoinject.Auto` annotation.
-	/// Not implementing this method implies all fields having `autoinject.Auto` annotation.
+	// Returns a mapping of field names to annotations.
+	// Omitted fields imply `autoinject.Auto` annotation.
+	// Not implementing this method implies all fields having `autoinject.Auto` annotation.
 	ProvideAutoInjectAnnotations() interface{}
 }
 
@@ -28,7 +28,7 @@ type autoInjectModule struct {
 	cached           bool
 }
 
-/// Create a module for automatically providing a struct type with the default `autoinject.Auto` annotation.
+// Create a module for automatically providing a struct type with the default `autoinject.Auto` annotation.
 func AutoInjectModule(typePointer interface{}) autoInjectModule {
 	return autoInjectModule{
 		typePointer:      typePointer,
@@ -38,25 +38,25 @@ func AutoInjectModule(typePointer interface{}) autoInjectModule {
 	}
 }
 
-/// Auto-inject the value with a custom annotation.
+// Auto-inject the value with a custom annotation.
 func (self autoInjectModule) WithAnnotation(annotation inject.Annotation) autoInjectModule {
 	self.annotation = annotation
 	return self
 }
 
-/// Auto-inject the value with a custom annotation.
+// Auto-inject the value with a custom annotation.
 func (self autoInjectModule) WithFieldAnnotations(fieldAnnotations interface{}) autoInjectModule {
 	self.fieldAnnotations = fieldAnnotations
 	return self
 }
 
-/// Make the generated provider cached.
+// Make the generated provider cached.
 func (self autoInjectModule) Cached() autoInjectModule {
 	self.cached = true
 	return self
 }
 
-/// Make the generated provider not cached.
+// Make the generated provider not cached.
 func (self autoInjectModule) NotCached() autoInjectModule {
 	self.cached = false
 	return self
@@ -0,0 +1,66 @@
+package inject
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// Benchmarks for getting values from multiple goroutines.
+// The "GlobalLock" variants serialize all `Get` calls with a single mutex, which is how the
+// injector used to work, to show the difference in throughput under contention.
+
+type benchmarkModule struct{}
+
+func (self benchmarkModule) ProvideCachedValue() (int, Annotation1) {
+	return testValue, Annotation1{}
+}
+
+func (self benchmarkModule) ProvideSlowValue() (int, Annotation2) {
+	time.Sleep(100 * time.Microsecond)
+	return testValue, Annotation2{}
+}
+
+func BenchmarkGetCached(b *testing.B) {
+	benchmarkGet(b, Annotation1{}, nil)
+}
+
+func BenchmarkGetCachedGlobalLock(b *testing.B) {
+	benchmarkGet(b, Annotation1{}, &sync.Mutex{})
+}
+
+func BenchmarkGetSlow(b *testing.B) {
+	benchmarkGet(b, Annotation2{}, nil)
+}
+
+func BenchmarkGetSlowGlobalLock(b *testing.B) {
+	benchmarkGet(b, Annotation2{}, &sync.Mutex{})
+}
+
+func benchmarkGet(b *testing.B, annotation Annotation, lock *sync.Mutex) {
+	injector, err := InjectorOf(benchmarkModule{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	// Build cached values before the benchmark starts.
+	if _, err := injector.Get(new(int), annotation); err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetParallelism(8)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if lock != nil {
+				lock.Lock()
+			}
+			_, err := injector.Get(new(int), annotation)
+			if lock != nil {
+				lock.Unlock()
+			}
+			if err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package inject
+
+import (
+	"reflect"
+)
+
+// Annotation for values that are provided by the injector itself, without any modules:
+//   - `*Injector`: the injector that provides the value that depends on it.
+//     Getting values from it while the provider runs resolves them as dependencies of the provided
+//     value, so dependency cycles are detected instead of deadlocking.
+//   - `*Lifecycle`: the lifecycle of the injector for registering start and stop hooks.
+type Builtin struct{}
+
+var injectorKey = Key{
+	valueType:      reflect.TypeOf((*Injector)(nil)),
+	annotationType: reflect.TypeOf(Builtin{}),
+}
+
+var lifecycleKey = Key{
+	valueType:      reflect.TypeOf((*Lifecycle)(nil)),
+	annotationType: reflect.TypeOf(Builtin{}),
+}
+
+func isBuiltin(key Key) bool {
+	return key == injectorKey || key == lifecycleKey
+}
+
+// Get a built-in value for the path's key.
+func (self *Injector) getBuiltin(path *dependencyPath) interface{} {
+	if path.key == lifecycleKey {
+		return self.lifecycle.boundTo(path.parent)
+	}
+	return self.boundTo(path.parent)
+}
+
+// Get an injector that gets values as dependencies of the path's key until its provider returns.
+func (self *Injector) boundTo(path *dependencyPath) *Injector {
+	return &Injector{
+		providers: self.providers,
+		cache:     self.cache,
+		lifecycle: self.lifecycle,
+		options:   self.options,
+		path:      path,
+		parent:    self.parent,
+		inherited: self.inherited,
+		scope:     self.scope,
+	}
+}
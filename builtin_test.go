@@ -0,0 +1,102 @@
+package inject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type BuiltinTests struct {
+	suite.Suite
+}
+
+type builtinTestModule struct {
+	injector **Injector
+}
+
+func (self builtinTestModule) ProvideCachedValue() (int, Annotation1) {
+	return testValue, Annotation1{}
+}
+
+func (self builtinTestModule) ProvideCachedDynamicValue(injector *Injector, _ Builtin) (int, Annotation2, error) {
+	*self.injector = injector
+	value, err := injector.Get(new(int), Annotation1{})
+	if err != nil {
+		return 0, Annotation2{}, err
+	}
+	return value.(int) + 1, Annotation2{}, nil
+}
+
+func (self builtinTestModule) ProvideCachedCycle(injector *Injector, _ Builtin) (int, Annotation3, error) {
+	value, err := injector.Get(new(int), Annotation3{})
+	if err != nil {
+		return 0, Annotation3{}, err
+	}
+	return value.(int), Annotation3{}, nil
+}
+
+func (self *BuiltinTests) TestGetFromProvider() {
+	injector, err := InjectorOf(builtinTestModule{injector: new(*Injector)})
+	self.Require().Nil(err)
+	value, err := injector.Get(new(int), Annotation2{})
+	self.Require().Nil(err)
+	self.Equal(testValue+1, value)
+}
+
+func (self *BuiltinTests) TestGetFromProviderCycle() {
+	injector, err := InjectorOf(builtinTestModule{injector: new(*Injector)})
+	self.Require().Nil(err)
+	_, err = injector.Get(new(int), Annotation3{})
+	self.Require().NotNil(err)
+	self.Equal(ProviderFailedError{
+		Key:  testKey(Annotation3{}),
+		Path: []Key{testKey(Annotation3{})},
+		Cause: CycleError{
+			Steps: []CycleStep{
+				{Key: testKey(Annotation3{})},
+				{Key: testKey(Annotation3{}), Lazy: true},
+			},
+			Path: []Key{testKey(Annotation3{}), testKey(Annotation3{})},
+		},
+	}, err)
+}
+
+func (self *BuiltinTests) TestGetAfterProviderReturned() {
+	module := builtinTestModule{injector: new(*Injector)}
+	injector, err := InjectorOf(module)
+	self.Require().Nil(err)
+	_, err = injector.Get(new(int), Annotation2{})
+	self.Require().Nil(err)
+
+	value, err := (*module.injector).Get(new(int), Annotation2{})
+	self.Require().Nil(err)
+	self.Equal(testValue+1, value)
+}
+
+func (self *BuiltinTests) TestGetInjector() {
+	injector, err := InjectorOf(builtinTestModule{injector: new(*Injector)})
+	self.Require().Nil(err)
+	value, err := injector.Get(new(*Injector), Builtin{})
+	self.Require().Nil(err)
+	self.Equal(testValue, value.(*Injector).MustGet(new(int), Annotation1{}))
+}
+
+type builtinTestInjectorModule struct{}
+
+func (self builtinTestInjectorModule) Provide() (*Injector, Builtin) {
+	return nil, Builtin{}
+}
+
+func (self *BuiltinTests) TestBuiltinCanNotBeProvided() {
+	_, err := InjectorOf(builtinTestInjectorModule{})
+	self.Require().NotNil(err)
+	self.Contains(err.Error(), "is provided by the injector")
+}
+
+func (self *BuiltinTests) TestValidateGraph() {
+	self.Nil(ValidateGraph(builtinTestModule{}))
+}
+
+func TestBuiltin(t *testing.T) {
+	suite.Run(t, new(BuiltinTests))
+}
@@ -0,0 +1,238 @@
+package inject
+
+import (
+	"errors"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// A cache of values of cached providers.
+// Each key is provided at most once: the first resolution of a key builds the value while
+// other resolutions of the same key wait for it, but resolutions of different keys are independent.
+type valuesCache struct {
+	// A map of provider keys to *cacheEntry-s.
+	// Reading already provided values does not take any locks.
+	entries sync.Map
+	// Guards cacheEntry.waitingFor of all entries.
+	waitLock sync.Mutex
+	// Set to 1 when the cache is closed.
+	closed int32
+	// Guards provided.
+	providedLock sync.Mutex
+	// Entries of successfully provided values in the order they were provided.
+	provided []*cacheEntry
+}
+
+// A cached value of a provider, or a value that is being provided.
+type cacheEntry struct {
+	key Key
+	// Closed after the value is provided.
+	done  chan struct{}
+	value interface{}
+	err   error
+	// While the resolution that provides this value waits for another entry, the path to that entry.
+	waitingFor *dependencyPath
+	// Whether the value was not provided because the context of the resolution is done.
+	// Such entries are not cached, and other resolutions provide the value again.
+	canceled bool
+	// Whether the provider or one of its dependencies panicked, in which case err is or wraps
+	// a `ProviderPanicError`. Such entries are not cached, but resolutions that wait for them fail.
+	panicked bool
+}
+
+// Get a cached value for the path's key, or provide and cache it.
+func (self *valuesCache) get(
+	path *dependencyPath,
+	provide func(path *dependencyPath) (interface{}, error),
+) (interface{}, error) {
+	if entry, ok := self.entries.Load(path.key); ok {
+		return self.getEntry(path, entry.(*cacheEntry), provide)
+	}
+	newEntry := &cacheEntry{key: path.key, done: make(chan struct{})}
+	if entry, loaded := self.entries.LoadOrStore(path.key, newEntry); loaded {
+		return self.getEntry(path, entry.(*cacheEntry), provide)
+	}
+	path.entry = newEntry
+
+	provided := false
+	defer func() {
+		// Do not cache panics: let waiting resolutions fail and next ones try again.
+		if !provided {
+			value := recover()
+			newEntry.panicked = true
+			newEntry.err = ProviderPanicError{Key: path.key, Path: path.keys(), Value: value, Stack: debug.Stack()}
+			self.entries.Delete(path.key)
+			close(newEntry.done)
+			// Recovering returns nil if the goroutine exits without panicking.
+			if value != nil {
+				panic(value)
+			}
+		}
+	}()
+	newEntry.value, newEntry.err = provide(path)
+	provided = true
+	if errors.As(newEntry.err, &ProviderPanicError{}) {
+		// The panic was recovered by the injector, possibly in a dependency that succeeds next time.
+		newEntry.panicked = true
+		self.entries.Delete(path.key)
+	} else if newEntry.err != nil && path.ctx.Err() != nil {
+		newEntry.canceled = true
+		self.entries.Delete(path.key)
+	}
+	if newEntry.err == nil {
+		self.providedLock.Lock()
+		self.provided = append(self.provided, newEntry)
+		self.providedLock.Unlock()
+	}
+	close(newEntry.done)
+	return newEntry.value, newEntry.err
+}
+
+// Close the cache and get entries of all values that were provided by it, in the order they
+// were provided.
+func (self *valuesCache) close() []*cacheEntry {
+	atomic.StoreInt32(&self.closed, 1)
+	self.providedLock.Lock()
+	defer self.providedLock.Unlock()
+	provided := self.provided
+	self.provided = nil
+	return provided
+}
+
+// Test if the cache is closed.
+func (self *valuesCache) isClosed() bool {
+	return atomic.LoadInt32(&self.closed) == 1
+}
+
+// Get the value of an entry that is provided by another resolution.
+// If the other resolution is canceled, provide the value again.
+func (self *valuesCache) getEntry(
+	path *dependencyPath,
+	entry *cacheEntry,
+	provide func(path *dependencyPath) (interface{}, error),
+) (interface{}, error) {
+	path.entry = entry
+	if err := self.wait(path); err != nil {
+		return nil, err
+	}
+	if entry.canceled {
+		path.entry = nil
+		return self.get(path, provide)
+	}
+	if entry.panicked {
+		// The entry is not cached, but this resolution waited for it and fails with the panic.
+		return nil, withResolutionPath(entry.err, path)
+	}
+	return entry.value, withResolutionPath(entry.err, path)
+}
+
+// Replace the start of the resolution path of the error of a cached entry, which is the path
+// of the resolution that provided the entry, with the path of the resolution that gets it.
+func withResolutionPath(err error, path *dependencyPath) error {
+	switch err := err.(type) {
+	case MissingProviderError:
+		err.Path = replacePathStart(err.Path, path)
+		return err
+	case ProviderFailedError:
+		err.Path = replacePathStart(err.Path, path)
+		return err
+	case ProviderPanicError:
+		err.Path = replacePathStart(err.Path, path)
+		return err
+	case CanceledError:
+		err.Path = replacePathStart(err.Path, path)
+		return err
+	case CycleError:
+		err.Path = replacePathStart(err.Path, path)
+		return err
+	default:
+		return err
+	}
+}
+
+func replacePathStart(keys []Key, path *dependencyPath) []Key {
+	for index, key := range keys {
+		if key == path.key {
+			return append(path.keys(), keys[index+1:]...)
+		}
+	}
+	return keys
+}
+
+// Wait for the entry of the path, that is being provided by another resolution.
+// Fails if the other resolution waits, possibly through other resolutions, for an entry
+// that is being provided by this resolution, as waiting would never finish.
+// Also fails if the context of the path is done before the entry is provided.
+func (self *valuesCache) wait(path *dependencyPath) error {
+	select {
+	case <-path.entry.done:
+		return nil
+	default:
+	}
+
+	providing := path.parent.providingEntries()
+	if len(providing) == 0 {
+		return waitDone(path)
+	}
+
+	self.waitLock.Lock()
+	if err := self.deadlock(path); err != nil {
+		self.waitLock.Unlock()
+		return err
+	}
+	for _, entry := range providing {
+		entry.waitingFor = path
+	}
+	self.waitLock.Unlock()
+
+	err := waitDone(path)
+
+	self.waitLock.Lock()
+	for _, entry := range providing {
+		entry.waitingFor = nil
+	}
+	self.waitLock.Unlock()
+	return err
+}
+
+// Wait for the entry of the path to be provided, or for the context of the path to be done.
+func waitDone(path *dependencyPath) error {
+	select {
+	case <-path.entry.done:
+		return nil
+	case <-path.ctx.Done():
+		return CanceledError{Key: path.key, Path: path.keys(), Cause: path.ctx.Err()}
+	}
+}
+
+// Build a cycle error if waiting for the entry of the path would never finish.
+// Must be called with waitLock held.
+func (self *valuesCache) deadlock(path *dependencyPath) error {
+	// Paths that wait for each other's entries: each one waits for an entry that is provided by
+	// the resolution of the next one.
+	waiting := []*dependencyPath{path}
+	visited := map[*cacheEntry]bool{}
+	for {
+		last := waiting[len(waiting)-1]
+		if visited[last.entry] {
+			// Other resolutions wait for each other, but not for this one.
+			return nil
+		}
+		visited[last.entry] = true
+		if start := path.parent.findEntry(last.entry); start != nil {
+			steps := path.stepsFrom(start)
+			previous := path.entry
+			for _, other := range waiting[1:] {
+				steps = append(steps, other.stepsFrom(other.findEntry(previous))[1:]...)
+				previous = other.entry
+			}
+			return CycleError{Steps: steps, Path: path.keys()}
+		}
+		next := last.entry.waitingFor
+		if next == nil {
+			return nil
+		}
+		waiting = append(waiting, next)
+	}
+}
@@ -0,0 +1,369 @@
+package inject
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ValuesCacheTests struct {
+	suite.Suite
+	injector *Injector
+}
+
+func (self *ValuesCacheTests) TestConcurrentGetsProvideOnce() {
+	calls := 0
+	release := make(chan struct{})
+	self.initInjector(map[Key]providerData{
+		testKey(Annotation1{}): {
+			provider: reflect.ValueOf(func() (int, Annotation1) {
+				<-release
+				calls += 1
+				return testValue, Annotation1{}
+			}),
+			arguments: []Key{},
+			cached:    true,
+		},
+	})
+
+	values := make(chan interface{})
+	for index := 0; index < 10; index += 1 {
+		go func() {
+			value, err := self.injector.Get(new(int), Annotation1{})
+			self.Nil(err)
+			values <- value
+		}()
+	}
+	close(release)
+	for index := 0; index < 10; index += 1 {
+		self.Equal(testValue, <-values)
+	}
+	self.Equal(1, calls)
+}
+
+func (self *ValuesCacheTests) TestIndependentKeysDoNotWait() {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	self.initInjector(map[Key]providerData{
+		testKey(Annotation1{}): {
+			provider: reflect.ValueOf(func() (int, Annotation1) {
+				close(started)
+				<-release
+				return testValue, Annotation1{}
+			}),
+			arguments: []Key{},
+			cached:    true,
+		},
+		testKey(Annotation2{}): {
+			provider: reflect.ValueOf(func() (int, Annotation2) {
+				return testValue + 1, Annotation2{}
+			}),
+			arguments: []Key{},
+			cached:    true,
+		},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		value, err := self.injector.Get(new(int), Annotation1{})
+		self.Nil(err)
+		self.Equal(testValue, value)
+	}()
+	<-started
+	value, err := self.injector.Get(new(int), Annotation2{})
+	self.Require().Nil(err)
+	self.Equal(testValue+1, value)
+	close(release)
+	<-done
+}
+
+func (self *ValuesCacheTests) TestPanicIsNotCached() {
+	shouldPanic := true
+	self.initInjector(map[Key]providerData{
+		testKey(Annotation1{}): {
+			provider: reflect.ValueOf(func() (int, Annotation1) {
+				if shouldPanic {
+					panic(testError)
+				}
+				return testValue, Annotation1{}
+			}),
+			arguments: []Key{},
+			cached:    true,
+		},
+	})
+	self.PanicsWithValue(testError, func() {
+		_, _ = self.injector.Get(new(int), Annotation1{})
+	})
+
+	shouldPanic = false
+	value, err := self.injector.Get(new(int), Annotation1{})
+	self.Require().Nil(err)
+	self.Equal(testValue, value)
+}
+
+func (self *ValuesCacheTests) TestWaitPanicked() {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := int32(0)
+	self.initInjector(map[Key]providerData{
+		testKey(Annotation1{}): {
+			provider: reflect.ValueOf(func() (int, Annotation1) {
+				if atomic.AddInt32(&calls, 1) > 1 {
+					return testValue, Annotation1{}
+				}
+				close(started)
+				<-release
+				panic(testError)
+			}),
+			arguments: []Key{},
+			cached:    true,
+		},
+		testKey(Annotation2{}): {
+			provider: reflect.ValueOf(func(value int, _ Annotation1) (int, Annotation2) {
+				return value, Annotation2{}
+			}),
+			arguments: []Key{testKey(Annotation1{})},
+		},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		self.PanicsWithValue(testError, func() {
+			_, _ = self.injector.Get(new(int), Annotation1{})
+		})
+	}()
+	<-started
+	errs := make(chan error)
+	go func() {
+		_, err := self.injector.Get(new(int), Annotation2{})
+		errs <- err
+	}()
+	// Let the second resolution start waiting.
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	<-done
+
+	// The second resolution either waited for the panicking provider, or called it again.
+	if err := <-errs; err != nil {
+		panicErr, ok := err.(ProviderPanicError)
+		self.Require().True(ok)
+		self.Equal(testKey(Annotation1{}), panicErr.Key)
+		self.Equal([]Key{testKey(Annotation2{}), testKey(Annotation1{})}, panicErr.Path)
+		self.Equal(testError, panicErr.Value)
+	}
+}
+
+// A context that reports when a resolution starts waiting for it to be done.
+type waitingContext struct {
+	context.Context
+	waiting chan struct{}
+	once    sync.Once
+}
+
+func (self *waitingContext) Done() <-chan struct{} {
+	self.once.Do(func() { close(self.waiting) })
+	return self.Context.Done()
+}
+
+func (self *ValuesCacheTests) TestWaitPanickedPath() {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	self.initInjector(map[Key]providerData{
+		testKey(Annotation1{}): {
+			provider: reflect.ValueOf(func() (int, Annotation1) {
+				close(started)
+				<-release
+				panic(testError)
+			}),
+			arguments: []Key{},
+			cached:    true,
+		},
+		testKey(Annotation2{}): {
+			provider: reflect.ValueOf(func(value int, _ Annotation1) (int, Annotation2) {
+				return value, Annotation2{}
+			}),
+			arguments: []Key{testKey(Annotation1{})},
+		},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		self.PanicsWithValue(testError, func() {
+			_, _ = self.injector.Get(new(int), Annotation1{})
+		})
+	}()
+	<-started
+	ctx := &waitingContext{Context: context.Background(), waiting: make(chan struct{})}
+	errs := make(chan error)
+	go func() {
+		_, err := self.injector.GetContext(ctx, new(int), Annotation2{})
+		errs <- err
+	}()
+	<-ctx.waiting
+	close(release)
+	<-done
+
+	err := <-errs
+	self.Require().IsType(ProviderPanicError{}, err)
+	panicErr := err.(ProviderPanicError)
+	self.Equal(testKey(Annotation1{}), panicErr.Key)
+	self.Equal([]Key{testKey(Annotation2{}), testKey(Annotation1{})}, panicErr.Path)
+	self.Equal(testError, panicErr.Value)
+}
+
+func (self *ValuesCacheTests) TestWaitCanceled() {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	self.initInjector(map[Key]providerData{
+		testKey(Annotation1{}): {
+			provider: reflect.ValueOf(func() (int, Annotation1) {
+				close(started)
+				<-release
+				return testValue, Annotation1{}
+			}),
+			arguments: []Key{},
+			cached:    true,
+		},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		value, err := self.injector.Get(new(int), Annotation1{})
+		self.Nil(err)
+		self.Equal(testValue, value)
+	}()
+	<-started
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := self.injector.GetContext(ctx, new(int), Annotation1{})
+	self.Equal(CanceledError{
+		Key:   testKey(Annotation1{}),
+		Path:  []Key{testKey(Annotation1{})},
+		Cause: context.Canceled,
+	}, err)
+	close(release)
+	<-done
+}
+
+func (self *ValuesCacheTests) TestCanceledIsProvidedAgain() {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	calls := int32(0)
+	self.initInjector(map[Key]providerData{
+		testKey(Annotation1{}): {
+			provider: reflect.ValueOf(func(value int, _ Annotation2) (int, Annotation1) {
+				atomic.AddInt32(&calls, 1)
+				return value, Annotation1{}
+			}),
+			arguments: []Key{testKey(Annotation2{})},
+			cached:    true,
+		},
+		testKey(Annotation2{}): {
+			provider: reflect.ValueOf(func() (int, Annotation2) {
+				select {
+				case started <- struct{}{}:
+				default:
+				}
+				<-release
+				return testValue, Annotation2{}
+			}),
+			arguments: []Key{},
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		_, err := self.injector.GetContext(ctx, new(int), Annotation1{})
+		canceled <- err
+	}()
+	<-started
+	values := make(chan interface{})
+	go func() {
+		value, err := self.injector.Get(new(int), Annotation1{})
+		self.Nil(err)
+		values <- value
+	}()
+	cancel()
+	close(release)
+
+	err := <-canceled
+	self.Require().NotNil(err)
+	self.Equal(CanceledError{
+		Key:   testKey(Annotation1{}),
+		Path:  []Key{testKey(Annotation1{})},
+		Cause: context.Canceled,
+	}, err)
+	self.Equal(testValue, <-values)
+	self.Equal(int32(1), atomic.LoadInt32(&calls))
+}
+
+func (self *ValuesCacheTests) TestConcurrentLazyCycle() {
+	startedValue1 := make(chan struct{})
+	startedValue2 := make(chan struct{})
+	self.initInjector(map[Key]providerData{
+		testKey(Annotation1{}): {
+			provider: reflect.ValueOf(func(value func() int, _ Annotation2) (int, Annotation1) {
+				close(startedValue1)
+				<-startedValue2
+				return value(), Annotation1{}
+			}),
+			arguments: []Key{{
+				valueType:      reflect.TypeOf(func() int { return 0 }),
+				annotationType: reflect.TypeOf(Annotation2{}),
+			}},
+			cached: true,
+		},
+		testKey(Annotation2{}): {
+			provider: reflect.ValueOf(func(value func() int, _ Annotation1) (int, Annotation2) {
+				close(startedValue2)
+				<-startedValue1
+				return value(), Annotation2{}
+			}),
+			arguments: []Key{{
+				valueType:      reflect.TypeOf(func() int { return 0 }),
+				annotationType: reflect.TypeOf(Annotation1{}),
+			}},
+			cached: true,
+		},
+	})
+
+	errors := make([]error, 2)
+	wait := sync.WaitGroup{}
+	for index, annotation := range []Annotation{Annotation1{}, Annotation2{}} {
+		wait.Add(1)
+		go func(index int, annotation Annotation) {
+			defer wait.Done()
+			_, errors[index] = self.injector.Get(new(int), annotation)
+		}(index, annotation)
+	}
+	wait.Wait()
+	self.NotNil(errors[0])
+	self.NotNil(errors[1])
+	self.True(
+		isCycleError(errors[0]) || isCycleError(errors[1]),
+		"expected a dependency cycle, got %v and %v", errors[0], errors[1],
+	)
+}
+
+func isCycleError(err error) bool {
+	_, ok := err.(CycleError)
+	return ok
+}
+
+func (self *ValuesCacheTests) initInjector(providers map[Key]providerData) {
+	self.injector = newInjector(&providersData{providers: providers})
+}
+
+func TestValuesCache(t *testing.T) {
+	suite.Run(t, new(ValuesCacheTests))
+}
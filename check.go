@@ -1,8 +1,99 @@
 package inject
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // CheckModule checks if the object is in fact a Module.
 // Handles collections of modules correctly.
 func CheckModule(module Module) error {
 	_, err := buildProviders(module)
 	return err
 }
+
+// ValidateGraph checks that an injector can be created from the list of modules and that
+// all values it provides can be resolved: every dependency, strict or lazy, has a provider,
+// there are no dependency cycles and cached providers do not depend on scoped values.
+// Unlike `Injector.Get`, reports all missing dependencies at once.
+func ValidateGraph(modules ...Module) error {
+	providers, err := buildProviders(CombineModules(modules...))
+	if err != nil {
+		return err
+	}
+	if err := checkMissingDependencies(providers); err != nil {
+		return err
+	}
+	if err := checkCycles(providers); err != nil {
+		return err
+	}
+	return checkScopes(providers)
+}
+
+// A dependency of a provider that has no provider itself.
+type missingDependency struct {
+	key Key
+	// Whether the dependency is requested through a lazy argument.
+	lazy bool
+	// The key of the provider with the dependency.
+	dependent Key
+	// The module with the provider with the dependency.
+	module Module
+	// Provided keys that are similar to the dependency.
+	suggestions []Suggestion
+}
+
+func (self missingDependency) String() string {
+	kind := "dependency"
+	if self.lazy {
+		kind = "lazy dependency"
+	}
+	return fmt.Sprintf("%v: %s of %v in module %T%s",
+		self.key, kind, self.dependent, self.module, formatSuggestions(self.suggestions))
+}
+
+// An error for a module graph with dependencies that have no providers.
+type missingDependenciesError struct {
+	dependencies []missingDependency
+}
+
+func (self missingDependenciesError) Error() string {
+	lines := make([]string, len(self.dependencies))
+	for index, dependency := range self.dependencies {
+		lines[index] = dependency.String()
+	}
+	return fmt.Sprintf("No providers found for %d dependencies:\n\t%s",
+		len(self.dependencies), strings.Join(lines, "\n\t"))
+}
+
+func checkMissingDependencies(providers *providersData) error {
+	dependencies := []missingDependency{}
+	for key, provider := range providers.providers {
+		for _, argumentKey := range provider.arguments {
+			dependency := dependencyOn(argumentKey)
+			if _, ok := providers.providers[dependency.Key]; ok || isBuiltin(dependency.Key) {
+				continue
+			}
+			dependencies = append(dependencies, missingDependency{
+				key:         dependency.Key,
+				lazy:        dependency.Lazy,
+				dependent:   key,
+				module:      providers.modules[key],
+				suggestions: providers.suggestions(dependency.Key),
+			})
+		}
+	}
+	if len(dependencies) == 0 {
+		return nil
+	}
+
+	// Sort the dependencies to make the error deterministic.
+	sort.Slice(dependencies, func(i int, j int) bool {
+		if dependencies[i].key != dependencies[j].key {
+			return dependencies[i].key.String() < dependencies[j].key.String()
+		}
+		return dependencies[i].dependent.String() < dependencies[j].dependent.String()
+	})
+	return missingDependenciesError{dependencies: dependencies}
+}
@@ -36,3 +36,81 @@ func (self *CheckModuleTests) TestValid() {
 func TestCheckModule(t *testing.T) {
 	suite.Run(t, new(CheckModuleTests))
 }
+
+type ValidateGraphTests struct {
+	suite.Suite
+}
+
+func (self *ValidateGraphTests) TestEmptyModule() {
+	type testEmptyModule struct{}
+	self.Nil(ValidateGraph(testEmptyModule{}))
+}
+
+func (self *ValidateGraphTests) TestInvalidModule() {
+	self.NotNil(ValidateGraph(checkTestInvalidModule{}))
+}
+
+type checkTestValuesModule struct{}
+
+func (self checkTestValuesModule) ProvideValue() (int, Annotation1) {
+	return testValue, Annotation1{}
+}
+
+type checkTestDependentModule struct{}
+
+func (self checkTestDependentModule) ProvideValue(
+	value int, _ Annotation1,
+	lazyValue func() int, _ Annotation1,
+) (int, Annotation2) {
+	return value + lazyValue(), Annotation2{}
+}
+
+func (self *ValidateGraphTests) TestValid() {
+	self.Nil(ValidateGraph(checkTestValuesModule{}, checkTestDependentModule{}))
+}
+
+type checkTestMissingDependenciesModule struct{}
+
+func (self checkTestMissingDependenciesModule) ProvideValue(
+	value int, _ Annotation2,
+	lazyValue func() int, _ Annotation3,
+) (int, Annotation1) {
+	return value + lazyValue(), Annotation1{}
+}
+
+func (self *ValidateGraphTests) TestMissingDependencies() {
+	err := ValidateGraph(checkTestMissingDependenciesModule{})
+	self.Require().NotNil(err)
+	self.Equal(missingDependenciesError{dependencies: []missingDependency{
+		{
+			key:         testKey(Annotation2{}),
+			dependent:   testKey(Annotation1{}),
+			module:      checkTestMissingDependenciesModule{},
+			suggestions: []Suggestion{{Key: testKey(Annotation1{}), Reason: "different annotation"}},
+		},
+		{
+			key:         testKey(Annotation3{}),
+			lazy:        true,
+			dependent:   testKey(Annotation1{}),
+			module:      checkTestMissingDependenciesModule{},
+			suggestions: []Suggestion{{Key: testKey(Annotation1{}), Reason: "different annotation"}},
+		},
+	}}, err)
+	self.Equal("No providers found for 2 dependencies:\n"+
+		"\tint/inject.Annotation2: dependency of int/inject.Annotation1 "+
+		"in module inject.checkTestMissingDependenciesModule; "+
+		"did you mean int/inject.Annotation1 (different annotation)?\n"+
+		"\tint/inject.Annotation3: lazy dependency of int/inject.Annotation1 "+
+		"in module inject.checkTestMissingDependenciesModule; "+
+		"did you mean int/inject.Annotation1 (different annotation)?", err.Error())
+}
+
+func (self *ValidateGraphTests) TestCycle() {
+	err := ValidateGraph(cyclesTestCycleModule{})
+	self.Require().NotNil(err)
+	self.IsType(CycleError{}, err)
+}
+
+func TestValidateGraph(t *testing.T) {
+	suite.Run(t, new(ValidateGraphTests))
+}
@@ -0,0 +1,142 @@
+package inject
+
+import (
+	"fmt"
+)
+
+// Create a child injector with the modules, that also provides all values of this injector.
+// Values of cached providers of this injector are provided by it, so they are shared with the
+// child, unless they depend on keys of the child's modules, directly or through other dependencies.
+// Providers called by this injector get it as the built-in `*Injector`, so values that they get
+// from it do not depend on the child's modules.
+// The child's modules can not provide keys of this injector, see `OverridingChild`.
+// Closing the child injector only stops values that it provided, and its lifecycle only has hooks
+// appended by providers that it called.
+func (self *Injector) Child(modules ...Module) (*Injector, error) {
+	return self.child(false, modules)
+}
+
+// Same as `Child`, but keys of the child's modules replace keys of this injector in the child.
+// Values of this injector that depend on replaced keys are provided again by the child with
+// the child's values. As with `Override`, sets and maps are replaced as a whole.
+func (self *Injector) OverridingChild(modules ...Module) (*Injector, error) {
+	return self.child(true, modules)
+}
+
+func (self *Injector) child(override bool, modules []Module) (*Injector, error) {
+	childProviders, err := buildProviders(CombineModules(modules...))
+	if err != nil {
+		return nil, err
+	}
+	providers, err := mergeProviders(self.providers, childProviders, override)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCycles(providers); err != nil {
+		return nil, err
+	}
+	if err := checkScopes(providers); err != nil {
+		return nil, err
+	}
+
+	child := newInjector(providers)
+	child.options = self.options
+	child.parent = self
+	child.inherited = inheritedKeys(self.providers, providers, childProviders)
+	return child, nil
+}
+
+// Merge providers of a child injector into providers of its parent.
+func mergeProviders(parent *providersData, child *providersData, override bool) (*providersData, error) {
+	merged := &providersData{
+		providers: map[Key]providerData{},
+		modules:   map[Key]Module{},
+		locations: map[Key]sourceLocation{},
+		sets:      map[Key]*setBinding{},
+	}
+	for key, provider := range parent.providers {
+		merged.providers[key] = provider
+		merged.modules[key] = parent.modules[key]
+		if location, ok := parent.locations[key]; ok {
+			merged.locations[key] = location
+		}
+	}
+	for setKey, set := range parent.sets {
+		merged.sets[setKey] = set
+	}
+
+	childKeys := make([]Key, 0, len(child.providers))
+	for key := range child.providers {
+		childKeys = append(childKeys, key)
+	}
+	// Sort the keys to make errors deterministic.
+	sortKeys(childKeys)
+	for _, key := range childKeys {
+		if key.IsElement() {
+			continue
+		}
+		if _, ok := parent.providers[key]; !ok {
+			continue
+		}
+		if !override {
+			return nil, fmt.Errorf(
+				"Key %v is provided by the parent injector, use OverridingChild to override it", key)
+		}
+		// Elements of a replaced set are replaced with elements of the child's set, if any.
+		if set, ok := parent.sets[key]; ok {
+			for _, elementKey := range set.elements {
+				delete(merged.providers, elementKey)
+				delete(merged.modules, elementKey)
+				delete(merged.locations, elementKey)
+			}
+			delete(merged.sets, key)
+		}
+	}
+
+	for _, key := range childKeys {
+		merged.providers[key] = child.providers[key]
+		merged.modules[key] = child.modules[key]
+		delete(merged.locations, key)
+		if location, ok := child.locations[key]; ok {
+			merged.locations[key] = location
+		}
+	}
+	for setKey, set := range child.sets {
+		merged.sets[setKey] = set
+	}
+	return merged, nil
+}
+
+// Get keys of the merged providers that the parent injector provides for the child: cached keys
+// of the parent that do not depend on keys of the child, directly or through other dependencies.
+// Other keys are provided by the child itself, so that their scoped dependencies are provided
+// by scopes of the child.
+func inheritedKeys(parent *providersData, merged *providersData, child *providersData) map[Key]bool {
+	childOwned := map[Key]bool{}
+	for key := range child.providers {
+		childOwned[key] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for key, provider := range merged.providers {
+			if childOwned[key] {
+				continue
+			}
+			for _, argumentKey := range provider.arguments {
+				if childOwned[dependencyOn(argumentKey).Key] {
+					childOwned[key] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	inherited := map[Key]bool{}
+	for key := range merged.providers {
+		if provider, ok := parent.providers[key]; ok && provider.cached && !childOwned[key] {
+			inherited[key] = true
+		}
+	}
+	return inherited
+}
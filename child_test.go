@@ -0,0 +1,155 @@
+package inject
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ChildTests struct {
+	suite.Suite
+}
+
+type childTestParentModule struct {
+	calls *int
+}
+
+func (self childTestParentModule) ProvideCachedConnection() (*int, Annotation1) {
+	*self.calls += 1
+	value := testValue
+	return &value, Annotation1{}
+}
+
+func (self childTestParentModule) ProvideCachedTenant() (string, Annotation1) {
+	return "default", Annotation1{}
+}
+
+func (self childTestParentModule) ProvideCachedClient(
+	connection *int, _ Annotation1,
+	tenant string, _ Annotation1,
+) (string, Annotation2) {
+	return fmt.Sprintf("%s:%d", tenant, *connection), Annotation2{}
+}
+
+func (self childTestParentModule) ProvideIntoValues() (int, Annotation1) {
+	return 1, Annotation1{}
+}
+
+type childTestModule struct{}
+
+func (self childTestModule) ProvideHandler(client string, _ Annotation2) (string, Annotation3) {
+	return "handler:" + client, Annotation3{}
+}
+
+type childTestTenantModule struct {
+	tenant string
+}
+
+func (self childTestTenantModule) ProvideTenant() (string, Annotation1) {
+	return self.tenant, Annotation1{}
+}
+
+func (self childTestTenantModule) ProvideIntoValues() (int, Annotation1) {
+	return 2, Annotation1{}
+}
+
+func (self *ChildTests) parent(calls *int) *Injector {
+	injector, err := InjectorOf(childTestParentModule{calls: calls})
+	self.Require().Nil(err)
+	return injector
+}
+
+func (self *ChildTests) TestChild() {
+	calls := 0
+	parent := self.parent(&calls)
+	connection := MustGet[*int, Annotation1](parent)
+
+	child, err := parent.Child(childTestModule{})
+	self.Require().Nil(err)
+	self.Equal("handler:default:17", MustGet[string, Annotation3](child))
+	self.Equal(connection, MustGet[*int, Annotation1](child))
+	self.Equal(1, calls)
+	self.True(child.Has(KeyOf(new(*int), Annotation1{})))
+	self.Equal(childTestModule{}, child.Module(KeyOf(new(string), Annotation3{})))
+
+	// The parent does not see the child's keys.
+	_, err = Get[string, Annotation3](parent)
+	self.IsType(MissingProviderError{}, err)
+}
+
+func (self *ChildTests) TestChildConflict() {
+	calls := 0
+	_, err := self.parent(&calls).Child(childTestTenantModule{tenant: "tenant"})
+	self.Require().NotNil(err)
+	self.Equal(
+		"Key []int/inject.Annotation1 is provided by the parent injector, use OverridingChild to override it",
+		err.Error())
+}
+
+func (self *ChildTests) TestOverridingChild() {
+	calls := 0
+	parent := self.parent(&calls)
+	child, err := parent.OverridingChild(childTestTenantModule{tenant: "tenant"}, childTestModule{})
+	self.Require().Nil(err)
+
+	// The client depends on the overridden tenant, so the child provides it again,
+	// but the connection is still shared.
+	self.Equal("handler:tenant:17", MustGet[string, Annotation3](child))
+	self.Equal("default:17", MustGet[string, Annotation2](parent))
+	self.Equal(1, calls)
+	self.Equal([]int{2}, MustGet[[]int, Annotation1](child))
+	self.Equal([]int{1}, MustGet[[]int, Annotation1](parent))
+}
+
+type childTestDependentModule struct{}
+
+func (self childTestDependentModule) ProvideDependent(value int, _ Annotation4) (int, Annotation2) {
+	return value + 1, Annotation2{}
+}
+
+type childTestDependencyModule struct{}
+
+func (self childTestDependencyModule) ProvideDependency() (int, Annotation4) {
+	return testValue, Annotation4{}
+}
+
+func (self *ChildTests) TestDependencyProvidedByChild() {
+	parent, err := InjectorOf(childTestDependentModule{})
+	self.Require().Nil(err)
+	child, err := parent.Child(childTestDependencyModule{})
+	self.Require().Nil(err)
+	self.Equal(testValue+1, MustGet[int, Annotation2](child))
+}
+
+func (self *ChildTests) TestGrandchild() {
+	calls := 0
+	parent := self.parent(&calls)
+	child, err := parent.Child(childTestModule{})
+	self.Require().Nil(err)
+	grandchild, err := child.OverridingChild(childTestTenantModule{tenant: "tenant"})
+	self.Require().Nil(err)
+
+	self.Equal("handler:tenant:17", MustGet[string, Annotation3](grandchild))
+	self.Equal("handler:default:17", MustGet[string, Annotation3](child))
+	self.Equal(1, calls)
+}
+
+func (self *ChildTests) TestClose() {
+	calls := 0
+	parent := self.parent(&calls)
+	child, err := parent.Child(childTestModule{})
+	self.Require().Nil(err)
+	MustGet[string, Annotation3](child)
+	self.Require().Nil(child.Close(context.Background()))
+
+	_, err = Get[*int, Annotation1](child)
+	self.Equal(injectorClosedError, err)
+	_, err = Get[*int, Annotation1](parent)
+	self.Nil(err)
+}
+
+func TestChild(t *testing.T) {
+	suite.Run(t, new(ChildTests))
+}
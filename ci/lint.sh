@@ -4,52 +4,44 @@ set -e
 
 echo "Running $0 $*..."
 
-linter_config=(
-  --vendor
-  --line-length 120
-  --disable-all
-  --enable deadcode
-  --enable errcheck
-  --enable goconst
-  --enable gofmt
-  --enable gosec
-  --enable gosimple
-  --enable ineffassign
-  --enable interfacer
-  --enable lll
-  --enable maligned
-  --enable megacheck
-  --enable misspell
-  --enable nakedret
-  --enable staticcheck
-  --enable structcheck
-  --enable unconvert
-  --enable unparam
-  --enable unused
-  --enable varcheck
-  --enable vet
-  --enable vetshadow
-  # not compatible with our code style.
-  --disable golint
-  # gives a lot of false positives and does the same job as 'go build'
-  --disable gotype
-  --disable gotypex
-  # gives many false positives and is useless considering the use of 'unused' linter
-  --disable goimports
-  # gives a lot of false positives in the tests
-  --disable dupl
-  # this linter is useless in our approach to the development process
-  --disable gocyclo
-  # golang tests should be run from the project CI
-  --disable test
-  --disable testify
-  --exclude examples/weather.*
-)
-
-echo gometalinter \
-  "${linter_config[@]}" \
-  ./...
-
-gometalinter \
-  "${linter_config[@]}" \
-  ./...
+# Examples are not linted.
+packages=$(go list ./... | grep -v examples/weather)
+files=$(git ls-files '*.go' | grep -v '^examples/weather/')
+
+echo gofmt -l
+unformatted=$(gofmt -l $files)
+if [ -n "$unformatted" ]; then
+  echo "Files are not formatted with gofmt:"
+  echo "$unformatted"
+  exit 1
+fi
+
+echo go vet
+go vet $packages
+
+# Checks are configured in staticcheck.conf.
+echo staticcheck
+staticcheck $packages
+
+echo errcheck
+errcheck -ignoretests -ignorepkg fmt $packages
+
+echo ineffassign
+ineffassign $packages
+
+echo misspell
+misspell -error README.md $files
+
+# Lines of Go files must be at most 120 characters long, except for go:generate directives and
+# generated files.
+echo line length
+long_lines=$(echo "$files" | grep -v /testdata/ | xargs awk '
+  FNR == 1 { generated = /^\/\/ Code generated .* DO NOT EDIT\.$/ }
+  generated { next }
+  /^[ \t]*\/\/go:generate / { next }
+  length($0) > 120 { print FILENAME ":" FNR ": line is " length($0) " characters long" }
+')
+if [ -n "$long_lines" ]; then
+  echo "$long_lines"
+  exit 1
+fi
@@ -0,0 +1,91 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Stopper is an interface for values that need a context to be stopped.
+// The injector stops such values when it is closed.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+var injectorClosedError = errors.New("The injector is closed")
+
+// Close the injector.
+// All values of cached providers that were provided by the injector and implement `Stopper` or
+// `io.Closer` are stopped in the reverse order of providing them, so that values are stopped
+// before their dependencies. Values of not cached providers are not stopped, as the injector
+// does not own them. Values that are still being provided are not stopped either, so all
+// `Get` calls must return before closing the injector.
+// If the context is done, remaining values are not stopped.
+// Getting values from a closed injector fails. Closing it again does nothing.
+// Closing a scope only stops values of scoped providers that it provided, see `NewScope`.
+// Returns errors of all values that failed to stop.
+func (self *Injector) Close(ctx context.Context) error {
+	cache := self.cache
+	if self.scope != nil {
+		cache = self.scope
+	}
+	entries := cache.close()
+	// The same value can be provided for multiple keys, but it only needs to be stopped once:
+	// after all values that were provided after it the first time.
+	firstIndices := map[interface{}]int{}
+	for index := len(entries) - 1; index >= 0; index -= 1 {
+		if isComparable(entries[index].value) {
+			firstIndices[entries[index].value] = index
+		}
+	}
+
+	errs := errorList{}
+	for index := len(entries) - 1; index >= 0; index -= 1 {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		entry := entries[index]
+		if isComparable(entry.value) && firstIndices[entry.value] != index {
+			continue
+		}
+		if err := stopValue(ctx, entry.value); err != nil {
+			errs = append(errs, closeError{key: entry.key, cause: err})
+		}
+	}
+	return errs.asError()
+}
+
+// Test if the value can be a map key. Values of comparable types can still panic when compared,
+// for example structs with interface fields that hold funcs, maps or slices.
+func isComparable(value interface{}) bool {
+	return value != nil && reflect.ValueOf(value).Comparable()
+}
+
+func stopValue(ctx context.Context, value interface{}) error {
+	switch typedValue := value.(type) {
+	case Stopper:
+		return typedValue.Stop(ctx)
+	case io.Closer:
+		return typedValue.Close()
+	default:
+		return nil
+	}
+}
+
+type closeError struct {
+	key   Key
+	cause error
+}
+
+func (self closeError) Error() string {
+	return fmt.Sprintf("error closing type %v with annotation %v: %s",
+		self.key.valueType, self.key.annotationType, self.cause.Error())
+}
+
+func (self closeError) Unwrap() error {
+	return self.cause
+}
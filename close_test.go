@@ -0,0 +1,174 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CloseTests struct {
+	suite.Suite
+}
+
+type testCloser struct {
+	name   string
+	closed *[]string
+	err    error
+}
+
+func (self *testCloser) Close() error {
+	*self.closed = append(*self.closed, self.name)
+	return self.err
+}
+
+type testStopper struct {
+	testCloser
+}
+
+func (self *testStopper) Stop(ctx context.Context) error {
+	*self.closed = append(*self.closed, self.name+" with context")
+	return self.err
+}
+
+type closeTestModule struct {
+	closed *[]string
+	errs   map[string]error
+}
+
+func (self closeTestModule) closer(name string) *testCloser {
+	return &testCloser{name: name, closed: self.closed, err: self.errs[name]}
+}
+
+func (self closeTestModule) ProvideCachedBase() (*testCloser, Annotation1) {
+	return self.closer("base"), Annotation1{}
+}
+
+func (self closeTestModule) ProvideCachedDependent(
+	base *testCloser, _ Annotation1,
+	stopper *testStopper, _ Annotation3,
+) (*testCloser, Annotation2) {
+	return self.closer("dependent"), Annotation2{}
+}
+
+func (self closeTestModule) ProvideCachedStopper() (*testStopper, Annotation3) {
+	return &testStopper{*self.closer("stopper")}, Annotation3{}
+}
+
+func (self closeTestModule) ProvideNotCached() (*testCloser, Annotation3) {
+	return self.closer("not cached"), Annotation3{}
+}
+
+func (self closeTestModule) ProvideCachedSameValue(value *testCloser, _ Annotation1) (*testCloser, Annotation4) {
+	return value, Annotation4{}
+}
+
+type Annotation4 struct{}
+
+// A struct value that is not comparable at runtime, because its interface field holds a func.
+type testConfig struct {
+	handler interface{}
+	closed  *[]string
+}
+
+func (self testConfig) Close() error {
+	*self.closed = append(*self.closed, "config")
+	return nil
+}
+
+type closeTestConfigModule struct {
+	closed *[]string
+}
+
+func (self closeTestConfigModule) ProvideCachedConfig() (testConfig, Annotation1) {
+	return testConfig{handler: func() {}, closed: self.closed}, Annotation1{}
+}
+
+func (self *CloseTests) TestCloseInReverseOrder() {
+	closed := []string{}
+	injector, err := InjectorOf(closeTestModule{closed: &closed})
+	self.Require().Nil(err)
+	_ = injector.MustGet(new(*testCloser), Annotation2{})
+	_ = injector.MustGet(new(*testCloser), Annotation3{})
+	_ = injector.MustGet(new(*testCloser), Annotation4{})
+
+	self.Require().Nil(injector.Close(context.Background()))
+	self.Equal([]string{"dependent", "stopper with context", "base"}, closed)
+}
+
+func (self *CloseTests) TestCloseOnlyProvidedValues() {
+	closed := []string{}
+	injector, err := InjectorOf(closeTestModule{closed: &closed})
+	self.Require().Nil(err)
+	_ = injector.MustGet(new(*testCloser), Annotation1{})
+
+	self.Require().Nil(injector.Close(context.Background()))
+	self.Equal([]string{"base"}, closed)
+}
+
+func (self *CloseTests) TestCloseNotComparableValues() {
+	closed := []string{}
+	injector, err := InjectorOf(closeTestConfigModule{closed: &closed})
+	self.Require().Nil(err)
+	_ = injector.MustGet(new(testConfig), Annotation1{})
+
+	self.Require().Nil(injector.Close(context.Background()))
+	self.Equal([]string{"config"}, closed)
+}
+
+func (self *CloseTests) TestCloseErrors() {
+	closed := []string{}
+	baseError := errors.New("base error")
+	dependentError := errors.New("dependent error")
+	injector, err := InjectorOf(closeTestModule{closed: &closed, errs: map[string]error{
+		"base":      baseError,
+		"dependent": dependentError,
+	}})
+	self.Require().Nil(err)
+	_ = injector.MustGet(new(*testCloser), Annotation2{})
+
+	err = injector.Close(context.Background())
+	self.Require().NotNil(err)
+	self.Equal(errorList{
+		closeError{key: testCloserKey(Annotation2{}), cause: dependentError},
+		closeError{key: testCloserKey(Annotation1{}), cause: baseError},
+	}, err)
+	self.True(errors.Is(err, dependentError))
+	self.True(errors.Is(err, baseError))
+	self.Equal([]string{"dependent", "stopper with context", "base"}, closed)
+}
+
+func (self *CloseTests) TestCloseCanceled() {
+	closed := []string{}
+	injector, err := InjectorOf(closeTestModule{closed: &closed})
+	self.Require().Nil(err)
+	_ = injector.MustGet(new(*testCloser), Annotation2{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	self.Equal(context.Canceled, injector.Close(ctx))
+	self.Equal([]string{}, closed)
+}
+
+func (self *CloseTests) TestGetAfterClose() {
+	closed := []string{}
+	injector, err := InjectorOf(closeTestModule{closed: &closed})
+	self.Require().Nil(err)
+	self.Require().Nil(injector.Close(context.Background()))
+
+	_, err = injector.Get(new(*testCloser), Annotation1{})
+	self.Equal(injectorClosedError, err)
+	self.Nil(injector.Close(context.Background()))
+}
+
+func testCloserKey(annotation Annotation) Key {
+	key := testKey(annotation)
+	key.valueType = reflect.TypeOf((*testCloser)(nil))
+	return key
+}
+
+func TestClose(t *testing.T) {
+	suite.Run(t, new(CloseTests))
+}
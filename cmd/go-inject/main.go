@@ -0,0 +1,158 @@
+// go-inject inspects and validates the module graph of a go-inject module.
+//
+// Usage:
+//
+//	go-inject <package> <function> <command> [arguments]
+//
+// The function is an exported function of the package without arguments that returns
+// the module to inspect. The tool generates a temporary program in the package's directory that
+// calls the function and runs the command on the module, so that the program is built with
+// the package's dependencies. Because of that, the package can not be a main package.
+// See the inspect package for the commands.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/monnoroch/go-inject/inspect"
+)
+
+const usage = `Usage: go-inject <package> <function> <command> [arguments]
+
+The function is an exported function of the package without arguments returning the module.
+
+` + inspect.Usage
+
+var programTemplate = template.Must(template.New("program").Parse(`// Code generated by go-inject. DO NOT EDIT.
+
+package main
+
+import (
+	"github.com/monnoroch/go-inject/inspect"
+
+	target {{ printf "%q" .ImportPath }}
+)
+
+func main() {
+	inspect.Main(target.{{ .Function }}())
+}
+`))
+
+// A package, as reported by `go list`.
+type goPackage struct {
+	ImportPath string
+	Dir        string
+	Name       string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(arguments []string, stdout io.Writer, stderr io.Writer) int {
+	if len(arguments) < 3 {
+		fmt.Fprintln(stderr, usage)
+		return inspect.ExitUsage
+	}
+	packagePattern, function, commandArguments := arguments[0], arguments[1], arguments[2:]
+	if !token.IsIdentifier(function) || !token.IsExported(function) {
+		fmt.Fprintf(stderr, "%q is not an exported function name\n", function)
+		return inspect.ExitUsage
+	}
+
+	pkg, err := listPackage(packagePattern, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return inspect.ExitFailed
+	}
+	if pkg.Name == "main" {
+		fmt.Fprintf(stderr, "Package %s is a main package, which can not be imported\n", pkg.ImportPath)
+		return inspect.ExitFailed
+	}
+
+	program, err := generateProgram(pkg.ImportPath, function)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return inspect.ExitFailed
+	}
+	// The program is created in the package's directory to be built in the package's module.
+	dir, err := os.MkdirTemp(pkg.Dir, ".go-inject-")
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return inspect.ExitFailed
+	}
+	defer func() {
+		// Failing to remove the directory does not affect the result.
+		_ = os.RemoveAll(dir)
+	}()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), program, 0644); err != nil {
+		fmt.Fprintln(stderr, err)
+		return inspect.ExitFailed
+	}
+
+	// Build the program instead of running it with `go run` to get its exit code.
+	build := exec.Command("go", "build", "-o", "go-inject", "main.go")
+	build.Dir = dir
+	build.Stdout = stderr
+	build.Stderr = stderr
+	if err := build.Run(); err != nil {
+		fmt.Fprintf(stderr, "Failed to build the program for %s.%s: %v\n", pkg.ImportPath, function, err)
+		return inspect.ExitFailed
+	}
+
+	command := exec.Command(filepath.Join(dir, "go-inject"), commandArguments...)
+	command.Stdout = stdout
+	command.Stderr = stderr
+	if err := command.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintln(stderr, err)
+		return inspect.ExitFailed
+	}
+	return inspect.ExitOk
+}
+
+// Get the package from its pattern with `go list`.
+func listPackage(pattern string, stderr io.Writer) (goPackage, error) {
+	var output bytes.Buffer
+	command := exec.Command("go", "list", "-f", "{{.ImportPath}}\t{{.Dir}}\t{{.Name}}", pattern)
+	command.Stdout = &output
+	command.Stderr = stderr
+	if err := command.Run(); err != nil {
+		return goPackage{}, fmt.Errorf("Failed to list package %s: %v", pattern, err)
+	}
+	return parsePackage(output.String())
+}
+
+// Parse the output of `go list` for a single package.
+func parsePackage(output string) (goPackage, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 1 {
+		return goPackage{}, fmt.Errorf("Expected a single package, got %d", len(lines))
+	}
+	fields := strings.Split(lines[0], "\t")
+	if len(fields) != 3 {
+		return goPackage{}, fmt.Errorf("Unexpected go list output %q", lines[0])
+	}
+	return goPackage{ImportPath: fields[0], Dir: fields[1], Name: fields[2]}, nil
+}
+
+// Generate the program that runs the inspect commands on the module returned by the function of
+// the package.
+func generateProgram(importPath string, function string) ([]byte, error) {
+	var program bytes.Buffer
+	err := programTemplate.Execute(&program, struct {
+		ImportPath string
+		Function   string
+	}{importPath, function})
+	return program.Bytes(), err
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/monnoroch/go-inject/inspect"
+)
+
+type GoInjectTests struct {
+	suite.Suite
+}
+
+func (self *GoInjectTests) TestGenerateProgram() {
+	program, err := generateProgram("example.com/app/modules", "Module")
+	self.Require().Nil(err)
+	self.Equal(`// Code generated by go-inject. DO NOT EDIT.
+
+package main
+
+import (
+	"github.com/monnoroch/go-inject/inspect"
+
+	target "example.com/app/modules"
+)
+
+func main() {
+	inspect.Main(target.Module())
+}
+`, string(program))
+
+	formatted, err := format.Source(program)
+	self.Nil(err)
+	self.Equal(string(program), string(formatted))
+}
+
+func (self *GoInjectTests) TestParsePackage() {
+	pkg, err := parsePackage("example.com/app/modules\t/src/app/modules\tmodules\n")
+	self.Nil(err)
+	self.Equal(goPackage{
+		ImportPath: "example.com/app/modules",
+		Dir:        "/src/app/modules",
+		Name:       "modules",
+	}, pkg)
+}
+
+func (self *GoInjectTests) TestParseMultiplePackages() {
+	_, err := parsePackage("a\t/a\ta\nb\t/b\tb\n")
+	self.NotNil(err)
+}
+
+func (self *GoInjectTests) TestNotEnoughArguments() {
+	var stderr bytes.Buffer
+	self.Equal(inspect.ExitUsage, run([]string{"./modules", "Module"}, &bytes.Buffer{}, &stderr))
+	self.Contains(stderr.String(), "Usage: go-inject")
+}
+
+func (self *GoInjectTests) TestNotExportedFunction() {
+	var stderr bytes.Buffer
+	self.Equal(inspect.ExitUsage, run([]string{"./modules", "module", "check"}, &bytes.Buffer{}, &stderr))
+	self.Equal("\"module\" is not an exported function name\n", stderr.String())
+}
+
+func TestGoInject(t *testing.T) {
+	suite.Run(t, new(GoInjectTests))
+}
@@ -0,0 +1,78 @@
+// inject-gen generates an injector for static go-inject modules that calls providers directly,
+// without reflection. See the codegen package for the generated code.
+//
+// Usage, in a file of the package with the modules:
+//
+//	//go:generate go run github.com/monnoroch/go-inject/cmd/inject-gen -modules Module,*OtherModule
+//
+// Flags:
+//
+//	-modules    comma-separated names of the module types, with a "*" prefix for pointers to them
+//	-type       the name of the generated injector type, "Injector" by default
+//	-output     the path of the generated file, "inject_gen.go" by default
+//	-dynamic    get keys without providers in the modules from the runtime injector
+//	-package    the package with the modules, the current directory by default
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/monnoroch/go-inject/codegen"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+func run(arguments []string, stderr io.Writer) int {
+	config, output, err := parseFlags(arguments, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	source, err := codegen.Generate(config)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if err := os.WriteFile(output, source, 0644); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// Parse the flags into the generator's configuration and the output file path.
+func parseFlags(arguments []string, stderr io.Writer) (codegen.Config, string, error) {
+	flags := flag.NewFlagSet("inject-gen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	modules := flags.String("modules", "", "comma-separated names of the module types")
+	typeName := flags.String("type", "Injector", "the name of the generated injector type")
+	output := flags.String("output", "inject_gen.go", "the name of the generated file")
+	dynamic := flags.Bool("dynamic", false, "get keys without providers in the modules from the runtime injector")
+	pkg := flags.String("package", ".", "the package with the modules")
+	if err := flags.Parse(arguments); err != nil {
+		return codegen.Config{}, "", err
+	}
+	if flags.NArg() != 0 {
+		return codegen.Config{}, "", fmt.Errorf("Unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+	if *modules == "" {
+		return codegen.Config{}, "", fmt.Errorf("No modules: set -modules")
+	}
+	if !token.IsIdentifier(*typeName) {
+		return codegen.Config{}, "", fmt.Errorf("%q is not a valid type name", *typeName)
+	}
+
+	return codegen.Config{
+		Package: *pkg,
+		Modules: strings.Split(*modules, ","),
+		Type:    *typeName,
+		Dynamic: *dynamic,
+	}, *output, nil
+}
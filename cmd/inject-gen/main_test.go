@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/monnoroch/go-inject/codegen"
+)
+
+type InjectGenTests struct {
+	suite.Suite
+}
+
+func (self *InjectGenTests) TestParseFlags() {
+	config, output, err := parseFlags([]string{
+		"-modules", "Module,*OtherModule",
+		"-type", "AppInjector",
+		"-output", "app_inject_gen.go",
+		"-dynamic",
+	}, &bytes.Buffer{})
+	self.Nil(err)
+	self.Equal(codegen.Config{
+		Package: ".",
+		Modules: []string{"Module", "*OtherModule"},
+		Type:    "AppInjector",
+		Dynamic: true,
+	}, config)
+	self.Equal("app_inject_gen.go", output)
+}
+
+func (self *InjectGenTests) TestDefaults() {
+	config, output, err := parseFlags([]string{"-modules", "Module"}, &bytes.Buffer{})
+	self.Nil(err)
+	self.Equal(codegen.Config{Package: ".", Modules: []string{"Module"}, Type: "Injector"}, config)
+	self.Equal("inject_gen.go", output)
+}
+
+func (self *InjectGenTests) TestNoModules() {
+	_, _, err := parseFlags([]string{}, &bytes.Buffer{})
+	self.NotNil(err)
+}
+
+func (self *InjectGenTests) TestInvalidType() {
+	_, _, err := parseFlags([]string{"-modules", "Module", "-type", "1Injector"}, &bytes.Buffer{})
+	self.NotNil(err)
+}
+
+func (self *InjectGenTests) TestUsageError() {
+	self.Equal(2, run([]string{"-modules", "Module", "extra"}, &bytes.Buffer{}))
+}
+
+func TestInjectGen(t *testing.T) {
+	suite.Run(t, new(InjectGenTests))
+}
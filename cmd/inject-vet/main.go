@@ -0,0 +1,20 @@
+// inject-vet checks go-inject modules statically. See the analyzer package for the checks.
+//
+// Usage:
+//
+//	inject-vet ./...
+//
+// or, together with the other vet checks:
+//
+//	go vet -vettool=$(which inject-vet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/monnoroch/go-inject/analyzer"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
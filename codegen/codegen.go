@@ -0,0 +1,438 @@
+// codegen generates injectors for static go-inject modules: plain Go code that calls providers
+// directly, without reflection.
+//
+// The generator reads the modules' types with their `Provide*` and `ProvideCached*` methods,
+// builds the dependency graph and generates an injector type with a constructor method for every
+// root key: a provided key that no other provider depends on. Generation fails if a dependency
+// has no provider or there is a dependency cycle through strict arguments, as the runtime injector
+// would fail to provide it.
+//
+// Generated injectors can be combined with the runtime injector: with `Config.Dynamic`, keys
+// without providers in the static modules, including the built-in `*inject.Injector` and
+// `*inject.Lifecycle`, are got from an `*inject.Injector` with the dynamic modules.
+//
+// Generated injectors behave like the runtime injector, with the following differences:
+//   - Errors of providers are `inject.ProviderFailedError`s and `inject.CanceledError`s without
+//     resolution paths.
+//   - Dependency cycles through lazy arguments fail with `inject.CycleError`s when a resolution
+//     requires a value that it is providing, but concurrent resolutions that wait for cached values
+//     of each other through such cycles deadlock.
+//   - Lazy arguments can be called after their providers return.
+//   - Panics of providers are not recovered.
+//   - Providers that take `inject.Handle` arguments, providers of set elements and scoped providers
+//     are not supported.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// The build tag of generated files: packages are loaded without it, so that outdated generated
+// files do not affect the generation.
+const BuildTag = "inject_gen"
+
+// The configuration of the generator.
+type Config struct {
+	// The package with the modules, as accepted by `go list`.
+	Package string
+	// Names of the module types in the package, with a "*" prefix for pointers to them.
+	Modules []string
+	// The name of the generated injector type. Its constructor is New<Type>.
+	Type string
+	// Whether keys without providers in the modules are got from the runtime injector.
+	Dynamic bool
+}
+
+// Generate the formatted source code of the injector.
+func Generate(config Config) ([]byte, error) {
+	if len(config.Modules) == 0 {
+		return nil, fmt.Errorf("No modules to generate the injector for")
+	}
+	pkg, err := loadPackage(config.Package)
+	if err != nil {
+		return nil, err
+	}
+	dependencyGraph, err := buildGraph(pkg.Types, pkg.Fset, config.Modules, config.Dynamic)
+	if err != nil {
+		return nil, err
+	}
+
+	generator := newGenerator(pkg.Types, config, dependencyGraph)
+	source := generator.generate()
+	formatted, err := format.Source(source)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to format the generated code: %v\n%s", err, source)
+	}
+	return formatted, nil
+}
+
+func loadPackage(pattern string) (*packages.Package, error) {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedImports | packages.NeedTypes | packages.NeedSyntax |
+			packages.NeedTypesInfo | packages.NeedDeps,
+		BuildFlags: []string{"-tags=" + BuildTag},
+	}, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("Expected a single package for %s, got %d", pattern, len(pkgs))
+	}
+	pkg := pkgs[0]
+	for _, pkgErr := range pkg.Errors {
+		// Type errors are expected: code of the package can use the injector that is not
+		// generated yet. Types of the modules are checked when building the graph.
+		if pkgErr.Kind != packages.TypeError {
+			return nil, pkgErr
+		}
+	}
+	return pkg, nil
+}
+
+// Generator of the injector's source code.
+type generator struct {
+	pkg     *types.Package
+	config  Config
+	graph   *graph
+	imports *imports
+	// Indices of the getters of keys.
+	getters map[string]int
+	body    bytes.Buffer
+}
+
+func newGenerator(pkg *types.Package, config Config, dependencyGraph *graph) *generator {
+	generator := &generator{
+		pkg:     pkg,
+		config:  config,
+		graph:   dependencyGraph,
+		imports: newImports(pkg),
+		getters: map[string]int{},
+	}
+	for _, provider := range dependencyGraph.providers {
+		generator.getters[provider.key.id()] = len(generator.getters)
+	}
+	for _, dynamicKey := range dependencyGraph.dynamic {
+		generator.getters[dynamicKey.id()] = len(generator.getters)
+	}
+	return generator
+}
+
+func (self *generator) printf(format string, arguments ...interface{}) {
+	fmt.Fprintf(&self.body, format, arguments...)
+}
+
+func (self *generator) generate() []byte {
+	self.generateType()
+	self.generateConstructor()
+	for _, root := range self.graph.roots {
+		self.generateRoot(root)
+	}
+	for _, provider := range self.graph.providers {
+		self.generateProvider(provider)
+	}
+	for _, dynamicKey := range self.graph.dynamic {
+		self.generateDynamic(dynamicKey)
+	}
+	if self.hasLazyArguments() {
+		self.printf("// A panic of a lazy argument of %s that failed to get its value.\n", self.config.Type)
+		self.printf("type %s struct {\n\terr error\n}\n", self.lazyErrorType())
+	}
+	if self.detectsCycles() {
+		self.generatePath()
+	}
+
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "// Code generated by inject-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&source, "//go:build !%s\n// +build !%s\n\n", BuildTag, BuildTag)
+	fmt.Fprintf(&source, "package %s\n\n", self.pkg.Name())
+	self.imports.write(&source)
+	source.Write(self.body.Bytes())
+	return source.Bytes()
+}
+
+func (self *generator) generateType() {
+	self.printf("// %s provides values of the modules %s without reflection.\n",
+		self.config.Type, strings.Join(self.config.Modules, ", "))
+	if self.config.Dynamic {
+		self.printf("// Values without providers in the modules are got from the runtime injector.\n")
+	}
+	self.printf("type %s struct {\n", self.config.Type)
+	for index, module := range self.graph.modules {
+		self.printf("module%d %s\n", index, self.typeString(module.moduleType))
+	}
+	if self.config.Dynamic {
+		self.printf("injector *%s.Injector\n", self.imports.name(injectPackagePath))
+	}
+	for _, provider := range self.graph.providers {
+		if !provider.cached {
+			continue
+		}
+		self.printf("\n// The cached value of %v.\n", provider.key)
+		self.printf("cache%d struct {\n", self.getters[provider.key.id()])
+		self.printf("%s.Mutex\ndone bool\nvalue %s\nerr error\n}\n",
+			self.imports.name("sync"), self.typeString(provider.key.valueType))
+	}
+	self.printf("}\n\n")
+}
+
+func (self *generator) generateConstructor() {
+	parameters := []string{}
+	fields := []string{}
+	for index, module := range self.graph.modules {
+		parameters = append(parameters, fmt.Sprintf("module%d %s", index, self.typeString(module.moduleType)))
+		fields = append(fields, fmt.Sprintf("module%d: module%d", index, index))
+	}
+	if self.config.Dynamic {
+		parameters = append(parameters, fmt.Sprintf("injector *%s.Injector", self.imports.name(injectPackagePath)))
+		fields = append(fields, "injector: injector")
+		self.printf("// Create an injector from the modules and the runtime injector that provides values\n")
+		self.printf("// without providers in them.\n")
+	} else {
+		self.printf("// Create an injector from the modules.\n")
+	}
+	self.printf("func New%s(%s) *%s {\n", self.config.Type, strings.Join(parameters, ", "), self.config.Type)
+	self.printf("return &%s{%s}\n}\n\n", self.config.Type, strings.Join(fields, ", "))
+}
+
+func (self *generator) generateRoot(root *provider) {
+	self.printf("// Get the value of %v.\n", root.key)
+	self.printf("func (self *%s) %s(ctx %s.Context) (%s, error) {\n",
+		self.config.Type, root.name, self.imports.name("context"), self.typeString(root.key.valueType))
+	self.printf("return self.get%d(ctx%s)\n}\n\n", self.getters[root.key.id()], self.pathArguments("nil", false))
+}
+
+func (self *generator) generateProvider(provider *provider) {
+	index := self.getters[provider.key.id()]
+	valueType := self.typeString(provider.key.valueType)
+	contextType := self.imports.name("context") + ".Context"
+	inject := self.imports.name(injectPackagePath)
+	module := self.graph.modules[provider.module]
+
+	// Only providers with arguments can require themselves, so only they continue the path.
+	tracksPath := self.detectsCycles() && len(provider.arguments) > 0
+	pathParameters := ""
+	if tracksPath {
+		pathParameters = fmt.Sprintf(", parent *%s, lazy bool", self.pathType())
+	} else if self.detectsCycles() {
+		pathParameters = fmt.Sprintf(", _ *%s, _ bool", self.pathType())
+	}
+	if provider.cached {
+		self.printf("// Get the cached value of %v.\n", provider.key)
+		self.printf("func (self *%s) get%d(ctx %s%s) (value %s, err error) {\n",
+			self.config.Type, index, contextType, pathParameters, valueType)
+		provideArguments := ""
+		if tracksPath {
+			self.printf("// Fail on cycles before locking the cache, which this resolution can be holding.\n")
+			self.generateChildPath(index)
+			provideArguments = ", path"
+		}
+		self.printf("self.cache%d.Lock()\ndefer self.cache%d.Unlock()\n", index, index)
+		self.printf("if self.cache%d.done {\nreturn self.cache%d.value, self.cache%d.err\n}\n", index, index, index)
+		self.printf("value, err = self.provide%d(ctx%s)\n", index, provideArguments)
+		self.printf("// Values are not cached if the context was done before they were provided.\n")
+		self.printf("if _, canceled := err.(%s.CanceledError); !canceled {\n", inject)
+		self.printf("self.cache%d.value, self.cache%d.err, self.cache%d.done = value, err, true\n}\n",
+			index, index, index)
+		self.printf("return value, err\n}\n\n")
+		self.printf("// Provide %v with %s.%s.\n", provider.key, module.name, provider.method.Name())
+		if tracksPath {
+			provideArguments = fmt.Sprintf(", path *%s", self.pathType())
+		}
+		self.printf("func (self *%s) provide%d(ctx %s%s) (value %s, err error) {\n",
+			self.config.Type, index, contextType, provideArguments, valueType)
+	} else {
+		self.printf("// Provide %v with %s.%s.\n", provider.key, module.name, provider.method.Name())
+		self.printf("func (self *%s) get%d(ctx %s%s) (value %s, err error) {\n",
+			self.config.Type, index, contextType, pathParameters, valueType)
+		if tracksPath {
+			self.generateChildPath(index)
+		}
+	}
+	if tracksPath && provider.hasLazyArguments() {
+		self.printf("// Lazy arguments called after the provider returns start new resolutions.\n")
+		self.printf("defer path.finish()\n")
+	}
+
+	arguments := []string{}
+	if provider.hasContext {
+		arguments = append(arguments, "ctx")
+	}
+	hasLazyArguments := false
+	for argumentIndex, argument := range provider.arguments {
+		getter := self.getters[argument.key.id()]
+		if argument.lazyReturnsError {
+			arguments = append(arguments, fmt.Sprintf(
+				"func() (%s, error) {\nreturn self.get%d(ctx%s)\n}",
+				self.typeString(argument.key.valueType), getter, self.pathArguments("path", true)))
+		} else if argument.lazy {
+			hasLazyArguments = true
+			arguments = append(arguments, fmt.Sprintf(
+				"func() %s {\nvalue, err := self.get%d(ctx%s)\nif err != nil {\npanic(%s{err: err})\n}\nreturn value\n}",
+				self.typeString(argument.key.valueType), getter, self.pathArguments("path", true),
+				self.lazyErrorType()))
+		} else {
+			self.printf("argument%d, err := self.get%d(ctx%s)\n", argumentIndex, getter, self.pathArguments("path", false))
+			self.printf("if err != nil {\nreturn value, err\n}\n")
+			arguments = append(arguments, fmt.Sprintf("argument%d", argumentIndex))
+		}
+		arguments = append(arguments, self.zeroValue(argument.key.annotationType))
+	}
+
+	keyExpression := fmt.Sprintf("%s.KeyOf(new(%s), %s)",
+		inject, valueType, self.zeroValue(provider.key.annotationType))
+	if len(arguments) > 0 {
+		self.printf("// Resolving arguments can take long, the context could be done by now.\n")
+	}
+	self.printf("if err := ctx.Err(); err != nil {\n")
+	self.printf("return value, %s.CanceledError{Key: %s, Cause: err}\n}\n", inject, keyExpression)
+	if hasLazyArguments {
+		self.printf("defer func() {\nif recovered := recover(); recovered != nil {\n")
+		self.printf("lazyErr, ok := recovered.(%s)\nif !ok {\npanic(recovered)\n}\n", self.lazyErrorType())
+		self.printf("err = lazyErr.err\n}\n}()\n")
+	}
+	call := fmt.Sprintf("self.module%d.%s(%s)", provider.module, provider.method.Name(), strings.Join(arguments, ", "))
+	if !provider.hasError {
+		self.printf("value, _ = %s\nreturn value, nil\n}\n\n", call)
+		return
+	}
+	self.printf("value, _, providerErr := %s\n", call)
+	self.printf("if providerErr != nil {\n")
+	self.printf("return value, %s.ProviderFailedError{Key: %s, Cause: providerErr}\n}\n", inject, keyExpression)
+	self.printf("return value, nil\n}\n\n")
+}
+
+func (self *generator) generateDynamic(dynamicKey key) {
+	valueType := self.typeString(dynamicKey.valueType)
+	self.printf("// Get %v from the runtime injector.\n", dynamicKey)
+	pathParameters := ""
+	if self.detectsCycles() {
+		pathParameters = fmt.Sprintf(", _ *%s, _ bool", self.pathType())
+	}
+	self.printf("func (self *%s) get%d(ctx %s.Context%s) (value %s, err error) {\n",
+		self.config.Type, self.getters[dynamicKey.id()], self.imports.name("context"), pathParameters, valueType)
+	self.printf("result, err := self.injector.GetContext(ctx, new(%s), %s)\n",
+		valueType, self.zeroValue(dynamicKey.annotationType))
+	self.printf("if err != nil {\nreturn value, err\n}\n")
+	self.printf("value, _ = result.(%s)\nreturn value, nil\n}\n\n", valueType)
+}
+
+func (self *generator) hasLazyArguments() bool {
+	for _, provider := range self.graph.providers {
+		for _, argument := range provider.arguments {
+			if argument.lazy && !argument.lazyReturnsError {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (self *generator) lazyErrorType() string {
+	return self.unexportedName("LazyError")
+}
+
+// Test if the injector can have dependency cycles at runtime: they can only go through lazy arguments.
+func (self *generator) detectsCycles() bool {
+	for _, provider := range self.graph.providers {
+		if provider.hasLazyArguments() {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the path arguments of a getter if the injector detects cycles.
+func (self *generator) pathArguments(path string, lazy bool) string {
+	if !self.detectsCycles() {
+		return ""
+	}
+	return fmt.Sprintf(", %s, %t", path, lazy)
+}
+
+// Continue the path of the parent with the key of the getter, returning an error if it is a cycle.
+func (self *generator) generateChildPath(index int) {
+	self.printf("path, err := parent.child(%d, lazy)\nif err != nil {\nreturn value, err\n}\n", index)
+}
+
+// Generate the type of resolution paths, with keys of all getters for cycle errors.
+func (self *generator) generatePath() {
+	inject := self.imports.name(injectPackagePath)
+	keys := make([]key, len(self.getters))
+	for _, provider := range self.graph.providers {
+		keys[self.getters[provider.key.id()]] = provider.key
+	}
+	for _, dynamicKey := range self.graph.dynamic {
+		keys[self.getters[dynamicKey.id()]] = dynamicKey
+	}
+	self.printf("\n// Keys of values of %s by the indices of their getters.\n", self.config.Type)
+	self.printf("var %s = [...]%s.Key{\n", self.keysVariable(), inject)
+	for _, valueKey := range keys {
+		self.printf("%s.KeyOf(new(%s), %s),\n",
+			inject, self.typeString(valueKey.valueType), self.zeroValue(valueKey.annotationType))
+	}
+	self.printf("}\n\n")
+
+	pathType := self.pathType()
+	keysVariable := self.keysVariable()
+	self.printf("// A chain of keys of %s that are being resolved, from the requested key to the current one.\n",
+		self.config.Type)
+	self.printf("type %s struct {\n", pathType)
+	self.printf("// The index of the getter of the key.\nkey int\n")
+	self.printf("// Whether the key was requested by calling a lazy argument of the parent's provider.\nlazy bool\n")
+	self.printf("parent *%s\n", pathType)
+	self.printf("// Set to 1 when the provider of the key returns.\nfinished int32\n}\n\n")
+
+	self.printf("// Continue the path with the key, failing if resolving the key requires itself.\n")
+	self.printf("func (self *%s) child(key int, lazy bool) (*%s, error) {\n", pathType, pathType)
+	self.printf("parent := self\n")
+	self.printf("if lazy && %s.LoadInt32(&parent.finished) == 1 {\n", self.imports.name("sync/atomic"))
+	self.printf("parent, lazy = nil, false\n}\n")
+	self.printf("path := &%s{key: key, lazy: lazy, parent: parent}\n", pathType)
+	self.printf("for start := parent; start != nil; start = start.parent {\n")
+	self.printf("if start.key != key {\ncontinue\n}\n")
+	self.printf("steps := []%s.CycleStep{}\n", inject)
+	self.printf("for element := path; element != start; element = element.parent {\n")
+	self.printf("steps = append([]%s.CycleStep{{Key: %s[element.key], Lazy: element.lazy}}, steps...)\n}\n",
+		inject, keysVariable)
+	self.printf("steps = append([]%s.CycleStep{{Key: %s[key]}}, steps...)\n", inject, keysVariable)
+	self.printf("keys := []%s.Key{}\n", inject)
+	self.printf("for element := path; element != nil; element = element.parent {\n")
+	self.printf("keys = append([]%s.Key{%s[element.key]}, keys...)\n}\n", inject, keysVariable)
+	self.printf("return nil, %s.CycleError{Steps: steps, Path: keys}\n}\n", inject)
+	self.printf("return path, nil\n}\n\n")
+
+	self.printf("// Mark the provider of the path's key as returned.\n")
+	self.printf("func (self *%s) finish() {\n%s.StoreInt32(&self.finished, 1)\n}\n",
+		pathType, self.imports.name("sync/atomic"))
+}
+
+func (self *generator) pathType() string {
+	return self.unexportedName("Path")
+}
+
+func (self *generator) keysVariable() string {
+	return self.unexportedName("Keys")
+}
+
+// Get an unexported name for a declaration of the injector type.
+func (self *generator) unexportedName(suffix string) string {
+	return strings.ToLower(self.config.Type[:1]) + self.config.Type[1:] + suffix
+}
+
+func (self *generator) typeString(valueType types.Type) string {
+	return types.TypeString(valueType, self.imports.qualifier)
+}
+
+// Get an expression for the zero value of the type.
+func (self *generator) zeroValue(valueType types.Type) string {
+	if _, ok := valueType.Underlying().(*types.Struct); ok {
+		return self.typeString(valueType) + "{}"
+	}
+	return fmt.Sprintf("*new(%s)", self.typeString(valueType))
+}
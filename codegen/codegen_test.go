@@ -0,0 +1,192 @@
+package codegen
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/tools/go/packages"
+)
+
+type CodegenTests struct {
+	suite.Suite
+	pkg *packages.Package
+}
+
+func (self *CodegenTests) SetupSuite() {
+	pkg, err := loadPackage("./testdata/modules")
+	self.Require().Nil(err)
+	self.pkg = pkg
+}
+
+func (self *CodegenTests) buildGraph(dynamic bool, modules ...string) (*graph, error) {
+	return buildGraph(self.pkg.Types, self.pkg.Fset, modules, dynamic)
+}
+
+func (self *CodegenTests) keys(providers []*provider) []string {
+	keys := []string{}
+	for _, provider := range providers {
+		keys = append(keys, provider.key.String())
+	}
+	return keys
+}
+
+func (self *CodegenTests) TestGraph() {
+	dependencyGraph, err := self.buildGraph(false, "ValidModule")
+	self.Require().Nil(err)
+	self.Equal([]string{"int/modules.Annotation1", "int/modules.Annotation2"}, self.keys(dependencyGraph.providers))
+	self.Equal([]string{"int/modules.Annotation2"}, self.keys(dependencyGraph.roots))
+	self.Equal("Dependent", dependencyGraph.roots[0].name)
+	self.True(dependencyGraph.roots[0].cached)
+	self.Empty(dependencyGraph.dynamic)
+}
+
+func (self *CodegenTests) TestPointerModule() {
+	dependencyGraph, err := self.buildGraph(false, "PointerModule")
+	self.Require().Nil(err)
+	self.Empty(dependencyGraph.providers)
+
+	dependencyGraph, err = self.buildGraph(false, "*PointerModule")
+	self.Require().Nil(err)
+	self.Equal([]string{"int/modules.Annotation1"}, self.keys(dependencyGraph.providers))
+}
+
+func (self *CodegenTests) TestUnknownModule() {
+	_, err := self.buildGraph(false, "UnknownModule")
+	self.Require().NotNil(err)
+	self.Equal(
+		"No type UnknownModule in package github.com/monnoroch/go-inject/codegen/testdata/modules",
+		err.Error())
+}
+
+func (self *CodegenTests) TestMissingDependencies() {
+	_, err := self.buildGraph(false, "MissingModule")
+	self.Require().NotNil(err)
+	self.Equal("No providers found for 2 dependencies:\n"+
+		"\tint/modules.Annotation2: dependency of int/modules.Annotation1 in module MissingModule\n"+
+		"\tstring/modules.Annotation2: lazy dependency of int/modules.Annotation1 in module MissingModule",
+		err.Error())
+}
+
+func (self *CodegenTests) TestDynamicDependencies() {
+	dependencyGraph, err := self.buildGraph(true, "MissingModule")
+	self.Require().Nil(err)
+	self.Equal(2, len(dependencyGraph.dynamic))
+	self.Equal("int/modules.Annotation2", dependencyGraph.dynamic[0].String())
+	self.Equal("string/modules.Annotation2", dependencyGraph.dynamic[1].String())
+}
+
+func (self *CodegenTests) TestBuiltin() {
+	_, err := self.buildGraph(false, "BuiltinModule")
+	self.Require().NotNil(err)
+	self.Contains(err.Error(), "built-in values are provided by the runtime injector, generate with -dynamic")
+	_, err = self.buildGraph(true, "BuiltinModule")
+	self.Nil(err)
+}
+
+func (self *CodegenTests) TestHandle() {
+	_, err := self.buildGraph(true, "HandleModule")
+	self.Require().NotNil(err)
+	self.Contains(err.Error(),
+		"provider ProvideValue takes a handle of int/modules.Annotation2, which only the runtime injector provides")
+}
+
+func (self *CodegenTests) TestSet() {
+	_, err := self.buildGraph(true, "SetModule")
+	self.Require().NotNil(err)
+	self.Contains(err.Error(),
+		"provider ProvideIntoValues provides an element of a set, which only the runtime injector provides")
+}
+
+func (self *CodegenTests) TestScoped() {
+	_, err := self.buildGraph(true, "ScopedModule")
+	self.Require().NotNil(err)
+	self.Contains(err.Error(), "provider ProvideScopedValue is scoped, which only the runtime injector supports")
+}
+
+func (self *CodegenTests) TestCycle() {
+	_, err := self.buildGraph(false, "CycleModule")
+	self.Require().NotNil(err)
+	self.Equal(
+		"Dependency cycle: int/modules.Annotation1 -> int/modules.Annotation2 -> int/modules.Annotation1",
+		err.Error())
+}
+
+func (self *CodegenTests) TestLazyCycle() {
+	_, err := self.buildGraph(false, "LazyCycleModule")
+	self.Nil(err)
+}
+
+func (self *CodegenTests) TestInvalidProvider() {
+	_, err := self.buildGraph(false, "InvalidModule")
+	self.Require().NotNil(err)
+	self.Contains(err.Error(), "modules.InvalidModule is not a module: it has an invalid provider ProvideValue")
+}
+
+func (self *CodegenTests) TestNotProvider() {
+	_, err := self.buildGraph(false, "NotProviderModule")
+	self.Require().NotNil(err)
+	self.Contains(err.Error(), "modules.NotProviderModule is not a module: it has an invalid provider Value")
+}
+
+func (self *CodegenTests) TestDynamicModule() {
+	_, err := self.buildGraph(false, "DynamicModule")
+	self.Require().NotNil(err)
+	self.Equal(
+		"github.com/monnoroch/go-inject/codegen/testdata/modules.DynamicModule is a dynamic module, "+
+			"which can only be provided by the runtime injector",
+		err.Error())
+}
+
+func (self *CodegenTests) TestDuplicateProviders() {
+	_, err := self.buildGraph(false, "ValidModule", "*PointerModule")
+	self.Require().NotNil(err)
+	self.Equal(
+		"Duplicate providers for key int/modules.Annotation1 in modules ValidModule and *PointerModule",
+		err.Error())
+}
+
+func (self *CodegenTests) TestSameConstructorNames() {
+	_, err := self.buildGraph(false, "SameNameModule")
+	self.Require().NotNil(err)
+	self.Equal(
+		"Root keys string/modules.Annotation1 and string/modules.Annotation2 have the same constructor name Value",
+		err.Error())
+}
+
+func (self *CodegenTests) TestNoModules() {
+	_, err := Generate(Config{Package: "./testdata/modules", Type: "Injector"})
+	self.NotNil(err)
+}
+
+// The generated injectors of the tests/generated package are up to date.
+func (self *CodegenTests) TestGenerate() {
+	for output, config := range map[string]Config{
+		"../tests/generated/inject_gen.go": {
+			Package: "../tests/generated",
+			Modules: []string{"ValuesModule", "*ServiceModule"},
+			Type:    "Injector",
+		},
+		"../tests/generated/dynamic_inject_gen.go": {
+			Package: "../tests/generated",
+			Modules: []string{"*ServiceModule", "RuntimeModule"},
+			Type:    "DynamicInjector",
+			Dynamic: true,
+		},
+		"../tests/generated/cycle_inject_gen.go": {
+			Package: "../tests/generated",
+			Modules: []string{"CycleModule"},
+			Type:    "CycleInjector",
+		},
+	} {
+		expected, err := os.ReadFile(output)
+		self.Require().Nil(err)
+		source, err := Generate(config)
+		self.Require().Nil(err)
+		self.Equal(string(expected), string(source), output)
+	}
+}
+
+func TestCodegen(t *testing.T) {
+	suite.Run(t, new(CodegenTests))
+}
@@ -0,0 +1,440 @@
+package codegen
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const providerPrefix = "Provide"
+const cachedProviderPrefix = providerPrefix + "Cached"
+const scopedProviderPrefix = providerPrefix + "Scoped"
+
+const injectPackagePath = "github.com/monnoroch/go-inject"
+
+// A key of the graph: a value type and an annotation type.
+type key struct {
+	valueType      types.Type
+	annotationType types.Type
+}
+
+// Get the string that identifies the key: types are qualified with full package paths.
+func (self key) id() string {
+	qualifier := func(pkg *types.Package) string {
+		return pkg.Path()
+	}
+	return types.TypeString(self.valueType, qualifier) + "/" + types.TypeString(self.annotationType, qualifier)
+}
+
+// Get the string representation of the key for messages, same as `inject.Key.String()`.
+func (self key) String() string {
+	qualifier := func(pkg *types.Package) string {
+		return pkg.Name()
+	}
+	return types.TypeString(self.valueType, qualifier) + "/" + types.TypeString(self.annotationType, qualifier)
+}
+
+// An argument of a provider.
+type argument struct {
+	// The key of the argument. For lazy arguments, the key of the lazily provided value.
+	key key
+	// The type of the argument, a function for lazy arguments.
+	argumentType types.Type
+	lazy         bool
+	// Whether the lazy argument returns the error of getting the value instead of panicking.
+	lazyReturnsError bool
+}
+
+// A provider method of a static module.
+type provider struct {
+	key    key
+	module int
+	method *types.Func
+	// The name of the constructor of the key if it is a root.
+	name       string
+	arguments  []argument
+	hasContext bool
+	hasError   bool
+	cached     bool
+}
+
+// Test if the provider has lazy arguments, which can form dependency cycles when they are called.
+func (self *provider) hasLazyArguments() bool {
+	for _, argument := range self.arguments {
+		if argument.lazy {
+			return true
+		}
+	}
+	return false
+}
+
+// A module, as given to the generator.
+type module struct {
+	name       string
+	moduleType types.Type
+}
+
+// The dependency graph of static modules.
+type graph struct {
+	modules []module
+	// Providers sorted by their keys.
+	providers []*provider
+	// Keys without providers in the modules, sorted.
+	dynamic []key
+	// Providers of keys that are not dependencies of other keys.
+	roots []*provider
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// Build the dependency graph of the modules.
+// Keys without providers are errors, unless they are allowed to be dynamic.
+func buildGraph(pkg *types.Package, fset *token.FileSet, moduleNames []string, dynamic bool) (*graph, error) {
+	result := &graph{}
+	providers := map[string]*provider{}
+	for index, name := range moduleNames {
+		moduleType, err := lookupModule(pkg, name)
+		if err != nil {
+			return nil, err
+		}
+		result.modules = append(result.modules, module{name: name, moduleType: moduleType})
+
+		moduleProviders, err := providersOf(pkg, fset, moduleType, index)
+		if err != nil {
+			return nil, err
+		}
+		for _, moduleProvider := range moduleProviders {
+			id := moduleProvider.key.id()
+			if existing, ok := providers[id]; ok {
+				return nil, fmt.Errorf(
+					"Duplicate providers for key %v in modules %s and %s",
+					moduleProvider.key, moduleNames[existing.module], name)
+			}
+			providers[id] = moduleProvider
+			result.providers = append(result.providers, moduleProvider)
+		}
+	}
+	sort.Slice(result.providers, func(i int, j int) bool {
+		return result.providers[i].key.id() < result.providers[j].key.id()
+	})
+
+	missing := []string{}
+	dynamicKeys := map[string]bool{}
+	dependents := map[string]bool{}
+	for _, dependent := range result.providers {
+		for _, argument := range dependent.arguments {
+			id := argument.key.id()
+			dependents[id] = true
+			if _, ok := providers[id]; ok || dynamicKeys[id] {
+				continue
+			}
+			if !dynamic {
+				missing = append(missing, missingDependency(argument, dependent, moduleNames))
+				continue
+			}
+			dynamicKeys[id] = true
+			result.dynamic = append(result.dynamic, argument.key)
+		}
+	}
+	if len(missing) != 0 {
+		return nil, fmt.Errorf(
+			"No providers found for %d dependencies:\n\t%s", len(missing), strings.Join(missing, "\n\t"))
+	}
+	sort.Slice(result.dynamic, func(i int, j int) bool {
+		return result.dynamic[i].id() < result.dynamic[j].id()
+	})
+
+	if err := checkCycles(result.providers, providers); err != nil {
+		return nil, err
+	}
+
+	names := map[string]*provider{}
+	for _, root := range result.providers {
+		if dependents[root.key.id()] {
+			continue
+		}
+		if !token.IsExported(root.name) {
+			return nil, fmt.Errorf(
+				"Root key %v has no valid constructor name: its provider %s has no exported name after %q",
+				root.key, root.method.Name(), providerPrefix)
+		}
+		if existing, ok := names[root.name]; ok {
+			return nil, fmt.Errorf(
+				"Root keys %v and %v have the same constructor name %s",
+				existing.key, root.key, root.name)
+		}
+		names[root.name] = root
+		result.roots = append(result.roots, root)
+	}
+	return result, nil
+}
+
+// Describe a missing dependency, similar to `inject.ValidateGraph`.
+func missingDependency(argument argument, dependent *provider, moduleNames []string) string {
+	kind := "dependency"
+	if argument.lazy {
+		kind = "lazy dependency"
+	}
+	description := fmt.Sprintf(
+		"%v: %s of %v in module %s", argument.key, kind, dependent.key, moduleNames[dependent.module])
+	if isBuiltin(argument.key) {
+		description += "; built-in values are provided by the runtime injector, generate with -dynamic"
+	}
+	return description
+}
+
+func isBuiltin(key key) bool {
+	return key.id() == "*"+injectPackagePath+".Injector/"+injectPackagePath+".Builtin" ||
+		key.id() == "*"+injectPackagePath+".Lifecycle/"+injectPackagePath+".Builtin"
+}
+
+// Test if the provider's name without its prefix is of a provider of a set element.
+func isIntoSet(name string) bool {
+	rest := strings.TrimPrefix(name, "Into")
+	return rest != name && rest != "" && unicode.IsUpper([]rune(rest)[0])
+}
+
+// Test if the provider's method name has the prefix of scoped providers.
+func isScoped(name string) bool {
+	rest := strings.TrimPrefix(name, scopedProviderPrefix)
+	return rest != name && rest != "" && unicode.IsUpper([]rune(rest)[0])
+}
+
+// Get the type of values of the handle if the argument type is an `inject.Handle`.
+func handleValueType(argumentType types.Type) types.Type {
+	named, ok := argumentType.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != injectPackagePath ||
+		named.Obj().Name() != "Handle" || named.TypeArgs().Len() != 1 {
+		return nil
+	}
+	return named.TypeArgs().At(0)
+}
+
+// Find the module type by its name in the package, with a "*" prefix for a pointer to it.
+func lookupModule(pkg *types.Package, name string) (types.Type, error) {
+	typeName := strings.TrimPrefix(name, "*")
+	object, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("No type %s in package %s", typeName, pkg.Path())
+	}
+	moduleType := object.Type()
+	if typeName != name {
+		moduleType = types.NewPointer(moduleType)
+	}
+	return moduleType, nil
+}
+
+// Get providers of a static module: its exported methods.
+func providersOf(pkg *types.Package, fset *token.FileSet, moduleType types.Type, moduleIndex int) ([]*provider, error) {
+	methods := types.NewMethodSet(moduleType)
+	providers := []*provider{}
+	for index := 0; index < methods.Len(); index += 1 {
+		method := methods.At(index).Obj().(*types.Func)
+		if !method.Exported() {
+			continue
+		}
+		if method.Name() == "Providers" {
+			return nil, fmt.Errorf(
+				"%v is a dynamic module, which can only be provided by the runtime injector", moduleType)
+		}
+
+		methodProvider := providerOf(method)
+		if methodProvider == nil || !strings.HasPrefix(method.Name(), providerPrefix) {
+			return nil, fmt.Errorf(
+				"%v: %v is not a module: it has an invalid provider %s",
+				fset.Position(method.Pos()), moduleType, method.Name())
+		}
+		if isIntoSet(methodProvider.name) {
+			return nil, fmt.Errorf(
+				"%v: provider %s provides an element of a set, which only the runtime injector provides",
+				fset.Position(method.Pos()), method.Name())
+		}
+		if isScoped(method.Name()) {
+			return nil, fmt.Errorf(
+				"%v: provider %s is scoped, which only the runtime injector supports",
+				fset.Position(method.Pos()), method.Name())
+		}
+		if err := checkAccessible(pkg, methodProvider); err != nil {
+			return nil, fmt.Errorf("%v: %v", fset.Position(method.Pos()), err)
+		}
+		for _, argument := range methodProvider.arguments {
+			if valueType := handleValueType(argument.argumentType); valueType != nil {
+				return nil, fmt.Errorf(
+					"%v: provider %s takes a handle of %v, which only the runtime injector provides",
+					fset.Position(method.Pos()), method.Name(),
+					key{valueType: valueType, annotationType: argument.key.annotationType})
+			}
+		}
+		methodProvider.module = moduleIndex
+		providers = append(providers, methodProvider)
+	}
+	return providers, nil
+}
+
+// Get the provider for the method, or nil if it is not a valid provider.
+// The rules are the same as for providers of the runtime injector.
+func providerOf(method *types.Func) *provider {
+	signature := method.Type().(*types.Signature)
+	results := signature.Results()
+	if results.Len() != 2 && results.Len() != 3 {
+		return nil
+	}
+	if results.Len() == 3 && !types.Implements(results.At(2).Type(), errorType.Underlying().(*types.Interface)) {
+		return nil
+	}
+
+	name := strings.TrimPrefix(method.Name(), providerPrefix)
+	cached := strings.HasPrefix(method.Name(), cachedProviderPrefix)
+	if cached {
+		name = strings.TrimPrefix(method.Name(), cachedProviderPrefix)
+	}
+	result := &provider{
+		key:      key{valueType: results.At(0).Type(), annotationType: results.At(1).Type()},
+		method:   method,
+		name:     name,
+		hasError: results.Len() == 3,
+		cached:   cached,
+	}
+
+	params := signature.Params()
+	firstInput := 0
+	if params.Len()%2 == 1 && isContext(params.At(0).Type()) {
+		firstInput = 1
+		result.hasContext = true
+	}
+	if (params.Len()-firstInput)%2 != 0 || signature.Variadic() {
+		return nil
+	}
+	for index := firstInput; index < params.Len(); index += 2 {
+		argumentType := params.At(index).Type()
+		argumentKey := key{valueType: argumentType, annotationType: params.At(index + 1).Type()}
+		lazyType := lazyArgumentType(argumentType)
+		if lazyType != nil {
+			argumentKey.valueType = lazyType
+		}
+		result.arguments = append(result.arguments, argument{
+			key:              argumentKey,
+			argumentType:     argumentType,
+			lazy:             lazyType != nil,
+			lazyReturnsError: lazyType != nil && argumentType.(*types.Signature).Results().Len() == 2,
+		})
+	}
+	return result
+}
+
+func isContext(valueType types.Type) bool {
+	named, ok := valueType.(*types.Named)
+	return ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// Get the type of the lazily provided value if the argument type is a lazy argument:
+// an unnamed function without arguments returning a single value, optionally with an error.
+func lazyArgumentType(argumentType types.Type) types.Type {
+	signature, ok := argumentType.(*types.Signature)
+	if !ok || signature.Params().Len() != 0 {
+		return nil
+	}
+	results := signature.Results()
+	switch {
+	case results.Len() == 1:
+		return results.At(0).Type()
+	case results.Len() == 2 && types.Identical(results.At(1).Type(), types.Universe.Lookup("error").Type()):
+		return results.At(0).Type()
+	default:
+		return nil
+	}
+}
+
+// Check that the generated code in the package can refer to all types of the provider.
+func checkAccessible(pkg *types.Package, provider *provider) error {
+	checked := []types.Type{provider.key.valueType, provider.key.annotationType}
+	for _, argument := range provider.arguments {
+		checked = append(checked, argument.argumentType, argument.key.annotationType)
+	}
+	for _, checkedType := range checked {
+		if err := checkTypeAccessible(pkg, checkedType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkTypeAccessible(pkg *types.Package, checkedType types.Type) error {
+	switch checkedType := checkedType.(type) {
+	case *types.Basic:
+		if checkedType.Kind() == types.Invalid {
+			return fmt.Errorf("Invalid type, the package has errors")
+		}
+	case *types.Named:
+		object := checkedType.Obj()
+		if object.Pkg() != nil && object.Pkg() != pkg && !object.Exported() {
+			return fmt.Errorf("Type %v is not exported from package %s", checkedType, object.Pkg().Path())
+		}
+	case *types.Pointer:
+		return checkTypeAccessible(pkg, checkedType.Elem())
+	case *types.Slice:
+		return checkTypeAccessible(pkg, checkedType.Elem())
+	case *types.Array:
+		return checkTypeAccessible(pkg, checkedType.Elem())
+	case *types.Chan:
+		return checkTypeAccessible(pkg, checkedType.Elem())
+	case *types.Map:
+		if err := checkTypeAccessible(pkg, checkedType.Key()); err != nil {
+			return err
+		}
+		return checkTypeAccessible(pkg, checkedType.Elem())
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{checkedType.Params(), checkedType.Results()} {
+			for index := 0; index < tuple.Len(); index += 1 {
+				if err := checkTypeAccessible(pkg, tuple.At(index).Type()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Check that no key depends on itself through strict arguments, like the runtime injector.
+func checkCycles(sortedProviders []*provider, providers map[string]*provider) error {
+	visited := map[string]bool{}
+	for _, root := range sortedProviders {
+		if err := checkCyclesFrom(root, []key{}, providers, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkCyclesFrom(current *provider, path []key, providers map[string]*provider, visited map[string]bool) error {
+	id := current.key.id()
+	for index, pathKey := range path {
+		if pathKey.id() == id {
+			steps := []string{}
+			for _, step := range append(path[index:], current.key) {
+				steps = append(steps, step.String())
+			}
+			return fmt.Errorf("Dependency cycle: %s", strings.Join(steps, " -> "))
+		}
+	}
+	if visited[id] {
+		return nil
+	}
+
+	path = append(path, current.key)
+	for _, argument := range current.arguments {
+		dependency, ok := providers[argument.key.id()]
+		if argument.lazy || !ok {
+			continue
+		}
+		if err := checkCyclesFrom(dependency, path, providers, visited); err != nil {
+			return err
+		}
+	}
+	visited[id] = true
+	return nil
+}
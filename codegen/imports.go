@@ -0,0 +1,98 @@
+package codegen
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Imports of the generated code, with unique names.
+type imports struct {
+	pkg *types.Package
+	// Names of imported packages by their paths.
+	names map[string]string
+	// Names that the packages declare, by their paths.
+	packageNames map[string]string
+	used         map[string]bool
+}
+
+func newImports(pkg *types.Package) *imports {
+	return &imports{
+		pkg:          pkg,
+		names:        map[string]string{},
+		packageNames: map[string]string{},
+		used:         map[string]bool{},
+	}
+}
+
+// Get the name of the imported package with the path, importing it if needed.
+// The package has to declare the last element of the path as its name, except for go-inject.
+func (self *imports) name(path string) string {
+	if name, ok := self.names[path]; ok {
+		return name
+	}
+	base := path[strings.LastIndex(path, "/")+1:]
+	if path == injectPackagePath {
+		base = "inject"
+	}
+	return self.add(path, base)
+}
+
+func (self *imports) add(path string, packageName string) string {
+	self.packageNames[path] = packageName
+	name := packageName
+	for suffix := 2; self.used[name] || self.pkg.Scope().Lookup(name) != nil; suffix += 1 {
+		name = fmt.Sprintf("%s%d", packageName, suffix)
+	}
+	self.names[path] = name
+	self.used[name] = true
+	return name
+}
+
+// Qualify types of packages for `types.TypeString`.
+func (self *imports) qualifier(pkg *types.Package) string {
+	if pkg == self.pkg {
+		return ""
+	}
+	if name, ok := self.names[pkg.Path()]; ok {
+		return name
+	}
+	return self.add(pkg.Path(), pkg.Name())
+}
+
+// Write the import declaration: standard library packages and then other packages, sorted by
+// their paths.
+func (self *imports) write(writer io.Writer) {
+	if len(self.names) == 0 {
+		return
+	}
+	paths := make([]string, 0, len(self.names))
+	for path := range self.names {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i int, j int) bool {
+		if isStandard(paths[i]) != isStandard(paths[j]) {
+			return isStandard(paths[i])
+		}
+		return paths[i] < paths[j]
+	})
+	fmt.Fprintf(writer, "import (\n")
+	for index, path := range paths {
+		if index > 0 && isStandard(paths[index-1]) && !isStandard(path) {
+			fmt.Fprintf(writer, "\n")
+		}
+		if name := self.names[path]; name != self.packageNames[path] {
+			fmt.Fprintf(writer, "%s %q\n", name, path)
+		} else {
+			fmt.Fprintf(writer, "%q\n", path)
+		}
+	}
+	fmt.Fprintf(writer, ")\n\n")
+}
+
+// Test if the package is in the standard library: its path has no domain.
+func isStandard(path string) bool {
+	return !strings.Contains(strings.SplitN(path, "/", 2)[0], ".")
+}
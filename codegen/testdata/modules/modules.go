@@ -0,0 +1,105 @@
+package modules
+
+import (
+	"github.com/monnoroch/go-inject"
+)
+
+type Annotation1 struct{}
+type Annotation2 struct{}
+
+type ValidModule struct{}
+
+func (self ValidModule) ProvideValue() (int, Annotation1) {
+	return 0, Annotation1{}
+}
+
+func (self ValidModule) ProvideCachedDependent(value int, _ Annotation1) (int, Annotation2) {
+	return value, Annotation2{}
+}
+
+type PointerModule struct{}
+
+func (self *PointerModule) ProvideValue() (int, Annotation1) {
+	return 0, Annotation1{}
+}
+
+type MissingModule struct{}
+
+func (self MissingModule) ProvideValue(
+	value int, _ Annotation2,
+	lazyValue func() string, _ Annotation2,
+) (int, Annotation1) {
+	return value, Annotation1{}
+}
+
+type CycleModule struct{}
+
+func (self CycleModule) ProvideValue(value int, _ Annotation2) (int, Annotation1) {
+	return value, Annotation1{}
+}
+
+func (self CycleModule) ProvideDependent(value int, _ Annotation1) (int, Annotation2) {
+	return value, Annotation2{}
+}
+
+type LazyCycleModule struct{}
+
+func (self LazyCycleModule) ProvideValue(value func() int, _ Annotation2) (int, Annotation1) {
+	return value(), Annotation1{}
+}
+
+func (self LazyCycleModule) ProvideDependent(value int, _ Annotation1) (int, Annotation2) {
+	return value, Annotation2{}
+}
+
+type InvalidModule struct{}
+
+func (self InvalidModule) ProvideValue() int {
+	return 0
+}
+
+type NotProviderModule struct{}
+
+func (self NotProviderModule) Value() (int, Annotation1) {
+	return 0, Annotation1{}
+}
+
+type BuiltinModule struct{}
+
+func (self BuiltinModule) ProvideValue(injector *inject.Injector, _ inject.Builtin) (int, Annotation1) {
+	return 0, Annotation1{}
+}
+
+type HandleModule struct{}
+
+func (self HandleModule) ProvideValue(value inject.Handle[int], _ Annotation2) (int, Annotation1) {
+	return 0, Annotation1{}
+}
+
+type SetModule struct{}
+
+func (self SetModule) ProvideIntoValues() (int, Annotation1) {
+	return 0, Annotation1{}
+}
+
+type ScopedModule struct{}
+
+func (self ScopedModule) ProvideScopedValue() (int, Annotation1) {
+	return 0, Annotation1{}
+}
+
+type SameNameModule struct{}
+
+func (self SameNameModule) ProvideValue() (string, Annotation1) {
+	return "", Annotation1{}
+}
+
+func (self SameNameModule) ProvideCachedValue() (string, Annotation2) {
+	return "", Annotation2{}
+}
+
+type DynamicModule struct{}
+
+func (self DynamicModule) Providers() ([]inject.Provider, error) {
+	return nil, nil
+}
@@ -0,0 +1,203 @@
+package inject
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// A chain of keys that are being resolved, from the requested key to the current one.
+// Used for detecting dependency cycles at runtime.
+type dependencyPath struct {
+	key Key
+	// Whether the key was requested by calling a lazy argument of the parent's provider.
+	lazy   bool
+	parent *dependencyPath
+	// The cache entry for the key if the provider is cached.
+	entry *cacheEntry
+	// Set to 1 when the provider of the key returns.
+	finished int32
+	// The context of the resolution.
+	ctx context.Context
+}
+
+// Create a path that continues this one with the key and the same context.
+// Can be called on a nil path to start a new one with the background context.
+func (self *dependencyPath) child(key Key, lazy bool) *dependencyPath {
+	ctx := context.Background()
+	if self != nil {
+		ctx = self.ctx
+	}
+	return self.childWithContext(ctx, key, lazy)
+}
+
+// Create a path that continues this one with the key and the context.
+// Can be called on a nil path to start a new one.
+func (self *dependencyPath) childWithContext(ctx context.Context, key Key, lazy bool) *dependencyPath {
+	return &dependencyPath{
+		key:    key,
+		lazy:   lazy,
+		parent: self,
+		ctx:    ctx,
+	}
+}
+
+// Mark the provider of the path's key as returned.
+func (self *dependencyPath) finish() {
+	atomic.StoreInt32(&self.finished, 1)
+}
+
+// Test if the provider of the path's key has returned.
+func (self *dependencyPath) isFinished() bool {
+	return atomic.LoadInt32(&self.finished) == 1
+}
+
+// Find the element of the path with the key, if any.
+func (self *dependencyPath) find(key Key) *dependencyPath {
+	for element := self; element != nil; element = element.parent {
+		if element.key == key {
+			return element
+		}
+	}
+	return nil
+}
+
+// Find the element of the path with the cache entry, if any.
+func (self *dependencyPath) findEntry(entry *cacheEntry) *dependencyPath {
+	for element := self; element != nil; element = element.parent {
+		if element.entry == entry {
+			return element
+		}
+	}
+	return nil
+}
+
+// Get cache entries of all elements of the path.
+func (self *dependencyPath) providingEntries() []*cacheEntry {
+	entries := []*cacheEntry{}
+	for element := self; element != nil; element = element.parent {
+		if element.entry != nil {
+			entries = append(entries, element.entry)
+		}
+	}
+	return entries
+}
+
+// Get the steps of the path from the element to the end of the path.
+func (self *dependencyPath) stepsFrom(start *dependencyPath) []CycleStep {
+	steps := []CycleStep{}
+	for element := self; element != start; element = element.parent {
+		steps = append(steps, CycleStep{Key: element.key, Lazy: element.lazy})
+	}
+	steps = append(steps, CycleStep{Key: start.key})
+	for left, right := 0, len(steps)-1; left < right; left, right = left+1, right-1 {
+		steps[left], steps[right] = steps[right], steps[left]
+	}
+	return steps
+}
+
+// Get the keys of the path, from the requested key to the current one.
+func (self *dependencyPath) keys() []Key {
+	keys := []Key{}
+	for element := self; element != nil; element = element.parent {
+		keys = append(keys, element.key)
+	}
+	for left, right := 0, len(keys)-1; left < right; left, right = left+1, right-1 {
+		keys[left], keys[right] = keys[right], keys[left]
+	}
+	return keys
+}
+
+// Build a cycle error if resolving this path's key requires itself.
+func (self *dependencyPath) cycle() error {
+	start := self.parent.find(self.key)
+	if start == nil {
+		return nil
+	}
+	return CycleError{Steps: self.stepsFrom(start), Path: self.keys()}
+}
+
+// An element of a dependency cycle.
+type CycleStep struct {
+	Key Key
+	// Whether the key is requested by the previous step through a lazy argument.
+	Lazy bool
+}
+
+// An error for a key that transitively depends on itself.
+// Strict dependencies are printed as "->" and lazy ones as "~>", because lazy dependencies
+// only form a cycle if they are actually called while the value is being provided.
+type CycleError struct {
+	// The steps of the cycle, starting and ending with the same key.
+	Steps []CycleStep
+	// The resolution path from the requested key to the key that requires itself.
+	Path []Key
+}
+
+func (self CycleError) Error() string {
+	hasLazySteps := false
+	path := strings.Builder{}
+	for index, step := range self.Steps {
+		if index > 0 {
+			if step.Lazy {
+				hasLazySteps = true
+				path.WriteString(" ~> ")
+			} else {
+				path.WriteString(" -> ")
+			}
+		}
+		path.WriteString(step.Key.String())
+	}
+	if hasLazySteps {
+		return fmt.Sprintf("Dependency cycle through lazy arguments: %s", path.String())
+	}
+	return fmt.Sprintf("Dependency cycle: %s", path.String())
+}
+
+// Check that no key depends on itself through strict arguments.
+// Lazy arguments are not checked: they can legitimately break cycles, and cycles through them
+// are detected when they are called.
+func checkCycles(providers *providersData) error {
+	keys := make([]Key, 0, len(providers.providers))
+	for key := range providers.providers {
+		keys = append(keys, key)
+	}
+	// Sort the keys to make the reported cycle deterministic.
+	sort.Slice(keys, func(i int, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	visited := map[Key]bool{}
+	for _, key := range keys {
+		if err := checkCyclesFrom((*dependencyPath)(nil).child(key, false), providers, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkCyclesFrom(path *dependencyPath, providers *providersData, visited map[Key]bool) error {
+	if err := path.cycle(); err != nil {
+		return err
+	}
+	if visited[path.key] {
+		return nil
+	}
+
+	provider, ok := providers.providers[path.key]
+	if !ok {
+		return nil
+	}
+	for _, argumentKey := range provider.arguments {
+		if getLazyArgumentType(argumentKey) != nil {
+			continue
+		}
+		if err := checkCyclesFrom(path.child(argumentKey, false), providers, visited); err != nil {
+			return err
+		}
+	}
+	visited[path.key] = true
+	return nil
+}
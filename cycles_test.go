@@ -0,0 +1,144 @@
+package inject
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CheckCyclesTests struct {
+	suite.Suite
+}
+
+type cyclesTestNoCycleModule struct{}
+
+func (self cyclesTestNoCycleModule) ProvideValue1() (int, Annotation1) {
+	return testValue, Annotation1{}
+}
+
+func (self cyclesTestNoCycleModule) ProvideValue2(value int, _ Annotation1) (int, Annotation2) {
+	return value, Annotation2{}
+}
+
+func (self cyclesTestNoCycleModule) ProvideValue3(
+	value1 int, _ Annotation1,
+	value2 int, _ Annotation2,
+) (int, Annotation3) {
+	return value1 + value2, Annotation3{}
+}
+
+func (self *CheckCyclesTests) TestNoCycle() {
+	self.Nil(self.checkCycles(cyclesTestNoCycleModule{}))
+}
+
+type cyclesTestSelfCycleModule struct{}
+
+func (self cyclesTestSelfCycleModule) ProvideValue(value int, _ Annotation1) (int, Annotation1) {
+	return value, Annotation1{}
+}
+
+func (self *CheckCyclesTests) TestSelfCycle() {
+	err := self.checkCycles(cyclesTestSelfCycleModule{})
+	self.Require().NotNil(err)
+	self.Equal("Dependency cycle: int/inject.Annotation1 -> int/inject.Annotation1", err.Error())
+}
+
+type cyclesTestCycleModule struct{}
+
+func (self cyclesTestCycleModule) ProvideInt(value string, _ Annotation2) (int, Annotation1) {
+	return len(value), Annotation1{}
+}
+
+func (self cyclesTestCycleModule) ProvideString(value int, _ Annotation1) (string, Annotation2) {
+	return "", Annotation2{}
+}
+
+func (self *CheckCyclesTests) TestCycle() {
+	err := self.checkCycles(cyclesTestCycleModule{})
+	self.Require().NotNil(err)
+	self.Equal(
+		"Dependency cycle: int/inject.Annotation1 -> string/inject.Annotation2 -> int/inject.Annotation1",
+		err.Error(),
+	)
+}
+
+type cyclesTestLazyCycleModule struct{}
+
+func (self cyclesTestLazyCycleModule) ProvideInt(value func() string, _ Annotation2) (int, Annotation1) {
+	return testValue, Annotation1{}
+}
+
+func (self cyclesTestLazyCycleModule) ProvideString(value int, _ Annotation1) (string, Annotation2) {
+	return "", Annotation2{}
+}
+
+func (self *CheckCyclesTests) TestLazyCycle() {
+	self.Nil(self.checkCycles(cyclesTestLazyCycleModule{}))
+}
+
+func (self *CheckCyclesTests) TestInjectorOf() {
+	_, err := InjectorOf(cyclesTestCycleModule{})
+	self.Require().NotNil(err)
+	self.IsType(CycleError{}, err)
+}
+
+func (self *CheckCyclesTests) checkCycles(module Module) error {
+	providers, err := buildProviders(module)
+	self.Require().Nil(err)
+	return checkCycles(providers)
+}
+
+func TestCheckCycles(t *testing.T) {
+	suite.Run(t, new(CheckCyclesTests))
+}
+
+type DependencyPathTests struct {
+	suite.Suite
+}
+
+func (self *DependencyPathTests) TestNoCycle() {
+	path := (*dependencyPath)(nil).
+		child(testKey(Annotation1{}), false).
+		child(testKey(Annotation2{}), false)
+	self.Nil(path.cycle())
+}
+
+func (self *DependencyPathTests) TestCycle() {
+	path := (*dependencyPath)(nil).
+		child(testKey(Annotation1{}), false).
+		child(testKey(Annotation2{}), false).
+		child(testKey(Annotation3{}), true).
+		child(testKey(Annotation2{}), false)
+	err := path.cycle()
+	self.Require().NotNil(err)
+	self.Equal(CycleError{
+		Steps: []CycleStep{
+			{Key: testKey(Annotation2{})},
+			{Key: testKey(Annotation3{}), Lazy: true},
+			{Key: testKey(Annotation2{})},
+		},
+		Path: []Key{
+			testKey(Annotation1{}),
+			testKey(Annotation2{}),
+			testKey(Annotation3{}),
+			testKey(Annotation2{}),
+		},
+	}, err)
+	self.Equal(
+		"Dependency cycle through lazy arguments: "+
+			"int/inject.Annotation2 ~> int/inject.Annotation3 -> int/inject.Annotation2",
+		err.Error(),
+	)
+}
+
+func testKey(annotation Annotation) Key {
+	return Key{
+		valueType:      reflect.TypeOf(int(0)),
+		annotationType: reflect.TypeOf(annotation),
+	}
+}
+
+func TestDependencyPath(t *testing.T) {
+	suite.Run(t, new(DependencyPathTests))
+}
@@ -0,0 +1,113 @@
+package inject
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Multiple errors reported together.
+type errorList []error
+
+// Get the list as an error: nil if it is empty and the only error if it has one.
+func (self errorList) asError() error {
+	switch len(self) {
+	case 0:
+		return nil
+	case 1:
+		return self[0]
+	default:
+		return self
+	}
+}
+
+func (self errorList) Error() string {
+	lines := make([]string, len(self))
+	for index, err := range self {
+		lines[index] = err.Error()
+	}
+	return fmt.Sprintf("%d errors:\n\t%s", len(self), strings.Join(lines, "\n\t"))
+}
+
+// Get the errors of the list, so that `errors.Is` and `errors.As` check all of them.
+func (self errorList) Unwrap() []error {
+	return self
+}
+
+// An error for a key that has no provider.
+type MissingProviderError struct {
+	// The key without a provider.
+	Key Key
+	// The resolution path from the requested key to the key without a provider.
+	Path []Key
+	// Provided keys that are similar to the key.
+	Suggestions []Suggestion
+}
+
+func (self MissingProviderError) Error() string {
+	return fmt.Sprintf("No provider found for %v%s%s",
+		self.Key, formatPath(self.Path), formatSuggestions(self.Suggestions))
+}
+
+// An error returned by the provider of a key.
+type ProviderFailedError struct {
+	// The key of the failed provider.
+	Key Key
+	// The resolution path from the requested key to the key of the failed provider.
+	Path []Key
+	// The error returned by the provider.
+	Cause error
+}
+
+func (self ProviderFailedError) Error() string {
+	return fmt.Sprintf("Provider of %v failed%s: %v", self.Key, formatPath(self.Path), self.Cause)
+}
+
+func (self ProviderFailedError) Unwrap() error {
+	return self.Cause
+}
+
+// An error for a provider that panicked.
+type ProviderPanicError struct {
+	// The key of the provider that panicked.
+	Key Key
+	// The resolution path from the requested key to the key of the provider that panicked.
+	Path []Key
+	// The value passed to panic.
+	Value interface{}
+	// The stack trace of the goroutine that panicked, as formatted by `debug.Stack`.
+	Stack []byte
+}
+
+func (self ProviderPanicError) Error() string {
+	return fmt.Sprintf("Provider of %v panicked%s: %v", self.Key, formatPath(self.Path), self.Value)
+}
+
+// An error for a key that was not provided because the context of the resolution is done.
+type CanceledError struct {
+	// The key that was not provided.
+	Key Key
+	// The resolution path from the requested key to the key that was not provided.
+	Path []Key
+	// The error of the context.
+	Cause error
+}
+
+func (self CanceledError) Error() string {
+	return fmt.Sprintf("Providing %v was canceled%s: %v", self.Key, formatPath(self.Path), self.Cause)
+}
+
+func (self CanceledError) Unwrap() error {
+	return self.Cause
+}
+
+// Format the resolution path for error messages, if it has more than the failing key.
+func formatPath(path []Key) string {
+	if len(path) <= 1 {
+		return ""
+	}
+	keys := make([]string, len(path))
+	for index, key := range path {
+		keys[index] = key.String()
+	}
+	return fmt.Sprintf(" (resolution path: %s)", strings.Join(keys, " -> "))
+}
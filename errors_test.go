@@ -0,0 +1,125 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ErrorsTests struct {
+	suite.Suite
+}
+
+type errorsTestModule struct{}
+
+func (self errorsTestModule) ProvideCachedFailing() (int, Annotation1, error) {
+	return 0, Annotation1{}, testError
+}
+
+func (self errorsTestModule) ProvideFirstDependent(value int, _ Annotation1) (int, Annotation2) {
+	return value, Annotation2{}
+}
+
+func (self errorsTestModule) ProvideSecondDependent(value int, _ Annotation1) (int, Annotation3) {
+	return value, Annotation3{}
+}
+
+func (self errorsTestModule) ProvideMissing(value int, _ Annotation4) (string, Annotation1) {
+	return "", Annotation1{}
+}
+
+type errorsTestFailingModule struct {
+	err error
+}
+
+func (self errorsTestFailingModule) ProvideCachedFirst() (int, Annotation1, error) {
+	return 0, Annotation1{}, testError
+}
+
+func (self errorsTestFailingModule) ProvideCachedSecond() (int, Annotation2, error) {
+	return 0, Annotation2{}, self.err
+}
+
+func (self *ErrorsTests) TestResolutionPath() {
+	injector, err := InjectorOf(errorsTestModule{})
+	self.Require().Nil(err)
+
+	_, err = injector.Get(new(int), Annotation2{})
+	self.Equal(ProviderFailedError{
+		Key:   testKey(Annotation1{}),
+		Path:  []Key{testKey(Annotation2{}), testKey(Annotation1{})},
+		Cause: testError,
+	}, err)
+
+	// The cached error has the path of the current resolution.
+	_, err = injector.Get(new(int), Annotation3{})
+	self.Equal(ProviderFailedError{
+		Key:   testKey(Annotation1{}),
+		Path:  []Key{testKey(Annotation3{}), testKey(Annotation1{})},
+		Cause: testError,
+	}, err)
+}
+
+func (self *ErrorsTests) TestAs() {
+	injector, err := InjectorOf(errorsTestModule{})
+	self.Require().Nil(err)
+
+	_, err = injector.Get(new(string), Annotation1{})
+	missingProviderErr := MissingProviderError{}
+	self.Require().True(errors.As(err, &missingProviderErr))
+	self.Equal(testKey(Annotation4{}), missingProviderErr.Key)
+
+	_, err = injector.Get(new(int), Annotation2{})
+	providerFailedErr := ProviderFailedError{}
+	self.Require().True(errors.As(err, &providerFailedErr))
+	self.Equal(testKey(Annotation1{}), providerFailedErr.Key)
+	self.True(errors.Is(err, testError))
+}
+
+func (self *ErrorsTests) TestAsList() {
+	secondError := errors.New("second error")
+	injector, err := InjectorOf(errorsTestFailingModule{err: secondError})
+	self.Require().Nil(err)
+
+	err = injector.InitAll(context.Background(), 1)
+	self.Require().IsType(errorList{}, err)
+	providerFailedErr := ProviderFailedError{}
+	self.Require().True(errors.As(err, &providerFailedErr))
+	self.Equal(testKey(Annotation1{}), providerFailedErr.Key)
+	self.True(errors.Is(err, testError))
+	self.True(errors.Is(err, secondError))
+}
+
+func (self *ErrorsTests) TestMessages() {
+	path := []Key{testKey(Annotation2{}), testKey(Annotation1{})}
+	self.Equal(
+		"No provider found for int/inject.Annotation1",
+		MissingProviderError{Key: testKey(Annotation1{}), Path: path[1:]}.Error(),
+	)
+	self.Equal(
+		"No provider found for int/inject.Annotation1 "+
+			"(resolution path: int/inject.Annotation2 -> int/inject.Annotation1)",
+		MissingProviderError{Key: testKey(Annotation1{}), Path: path}.Error(),
+	)
+	self.Equal(
+		"Provider of int/inject.Annotation1 failed "+
+			"(resolution path: int/inject.Annotation2 -> int/inject.Annotation1): test error",
+		ProviderFailedError{Key: testKey(Annotation1{}), Path: path, Cause: testError}.Error(),
+	)
+	self.Equal(
+		"Provider of int/inject.Annotation1 panicked "+
+			"(resolution path: int/inject.Annotation2 -> int/inject.Annotation1): test error",
+		ProviderPanicError{Key: testKey(Annotation1{}), Path: path, Value: testError}.Error(),
+	)
+	self.Equal(
+		"Providing int/inject.Annotation1 was canceled "+
+			"(resolution path: int/inject.Annotation2 -> int/inject.Annotation1): context canceled",
+		CanceledError{Key: testKey(Annotation1{}), Path: path, Cause: context.Canceled}.Error(),
+	)
+}
+
+func TestErrors(t *testing.T) {
+	suite.Run(t, new(ErrorsTests))
+}
@@ -12,12 +12,12 @@ import (
 	aiproto "github.com/monnoroch/go-inject/examples/weather/proto/ai"
 )
 
-/// A wrapper type around Ai client.
+// A wrapper type around Ai client.
 type AiClient struct {
 	RawAiClient aiproto.AiClient
 }
 
-/// Ask AI service for weather at location and time specified in arguments.
+// Ask AI service for weather at location and time specified in arguments.
 func (self *AiClient) AskForWeather(
 	ctx context.Context,
 	location string,
@@ -41,13 +41,13 @@ func (self AiClient) ProvideAutoInjectAnnotations() interface{} {
 	}{}
 }
 
-/// Annotation used by the AI service client module.
+// Annotation used by the AI service client module.
 type AiService struct{}
 
-/// Annotation for private providers.
+// Annotation for private providers.
 type private struct{}
 
-/// A module for providing AI service client components.
+// A module for providing AI service client components.
 type aiServiceClientModule struct{}
 
 func (_ aiServiceClientModule) ProvideCachedGrpcClient(
@@ -11,12 +11,12 @@ import (
 	blockchainproto "github.com/monnoroch/go-inject/examples/weather/proto/blockchain"
 )
 
-/// A wrapper type around blockchain client.
+// A wrapper type around blockchain client.
 type BlockchainClient struct {
 	RawBlockchainClient blockchainproto.BlockchainClient
 }
 
-/// Make payment using the blockchain service.
+// Make payment using the blockchain service.
 func (self *BlockchainClient) Pay(
 	ctx context.Context,
 	userId int64,
@@ -37,32 +37,39 @@ func (self BlockchainClient) ProvideAutoInjectAnnotations() interface{} {
 	}{}
 }
 
-/// Annotation used by the AI service client module.
+// Annotation used by the AI service client module.
 type BlockchainService struct{}
 
-/// Annotation for private providers.
+// Annotation for private providers.
 type private struct{}
 
-/// A module for providing AI service client components.
+// A module for providing AI service client components.
 type blockchainServiceClientModule struct{}
 
 func (_ blockchainServiceClientModule) ProvideCachedGrpcClient(
 	connection func() *grpc.ClientConn, _ BlockchainService,
-	develClient develBlockchainClient, _ private,
-	develInstance bool, _ BlockchainService,
 ) (blockchainproto.BlockchainClient, private) {
-	if develInstance {
-		return develClient, private{}
-	} else {
-		return blockchainproto.NewBlockchainClient(connection()), private{}
-	}
+	return blockchainproto.NewBlockchainClient(connection()), private{}
 }
 
 func BlockchainServiceClientModule() inject.Module {
 	return inject.CombineModules(
 		blockchainServiceClientModule{},
 		autoinject.AutoInjectModule(new(BlockchainClient)),
-		autoinject.AutoInjectModule(new(develBlockchainClient)).
-			WithAnnotation(private{}),
+	)
+}
+
+// A module for providing a development blockchain client instead of the real one.
+type develBlockchainServiceClientModule struct{}
+
+func (_ develBlockchainServiceClientModule) ProvideCachedGrpcClient() (blockchainproto.BlockchainClient, private) {
+	return develBlockchainClient{}, private{}
+}
+
+// Same as `BlockchainServiceClientModule`, but all payments succeed without calling the service.
+func DevelBlockchainServiceClientModule() inject.Module {
+	return inject.Override(
+		BlockchainServiceClientModule(),
+		develBlockchainServiceClientModule{},
 	)
 }
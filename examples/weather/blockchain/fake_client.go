@@ -10,7 +10,7 @@ import (
 
 type develBlockchainClient struct{}
 
-/// Make all payments succeed.
+// Make all payments succeed.
 func (self develBlockchainClient) Pay(
 	_ context.Context,
 	_ *blockchainproto.PayRequest,
@@ -32,7 +32,7 @@ func (self constantModule) Providers() ([]inject.Provider, error) {
 	)}, nil
 }
 
-/// Creates a module that provides a constant value with a specified annotation.
+// Creates a module that provides a constant value with a specified annotation.
 func ConstantModule(value interface{}, annotation inject.Annotation) inject.Module {
 	return constantModule{value: value, annotation: annotation}
 }
@@ -6,10 +6,10 @@ import (
 	"google.golang.org/grpc"
 )
 
-/// Annotation used by the gRPC client module.
+// Annotation used by the gRPC client module.
 type grpcClient struct{}
 
-/// A module for providing gRPC client components.
+// A module for providing gRPC client components.
 type grpcClientModule struct{}
 
 func (_ grpcClientModule) ProvideConnection(
@@ -28,10 +28,10 @@ func GrpcClientModule(annotation inject.Annotation) inject.Module {
 	)
 }
 
-/// Annotation used by the gRPC server module.
+// Annotation used by the gRPC server module.
 type GrpcServer struct{}
 
-/// A module for providing gRPC server components.
+// A module for providing gRPC server components.
 type GrpcServerModule struct{}
 
 func (_ GrpcServerModule) ProvideServer() (*grpc.Server, GrpcServer) {
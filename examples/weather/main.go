@@ -8,6 +8,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/monnoroch/go-inject"
+	"github.com/monnoroch/go-inject/app"
 	"github.com/monnoroch/go-inject/auto"
 	"github.com/monnoroch/go-inject/examples/weather/ai"
 	"github.com/monnoroch/go-inject/examples/weather/blockchain"
@@ -16,13 +17,13 @@ import (
 	proto "github.com/monnoroch/go-inject/examples/weather/proto"
 )
 
-/// The main server type for defining request handlers.
+// The main server type for defining request handlers.
 type Server struct {
 	AiClient         ai.AiClient
 	BlockchainClient blockchain.BlockchainClient
 }
 
-/// Handler for the WeatherPrediction.Predict RPCs.
+// Handler for the WeatherPrediction.Predict RPCs.
 func (self *Server) Predict(
 	ctx context.Context,
 	request *proto.SpaceTimeLocation,
@@ -38,45 +39,67 @@ func (self *Server) Predict(
 	return &proto.Weather{Weather: weather}, nil
 }
 
-/// Annotation used by the weather prediction server module.
+// Annotation used by the weather prediction server module.
 type WeatherPrediction struct{}
 
-/// A module for providing a configured weather prediction server.
+// A module for providing a configured weather prediction server.
 type weatherPredictionServerModule struct{}
 
-func (_ weatherPredictionServerModule) ProvideGrpcServer(
+func (_ weatherPredictionServerModule) ProvideCachedGrpcServer(
 	grpcServer *grpc.Server, _ grpcinject.GrpcServer,
 	weatherPredictionServer *Server, _ autoinject.Auto,
+	address string, _ WeatherPrediction,
+	lifecycle *inject.Lifecycle, _ inject.Builtin,
 ) (*grpc.Server, WeatherPrediction) {
 	proto.RegisterWeatherPredictionServer(
 		grpcServer,
 		weatherPredictionServer,
 	)
+	lifecycle.Append(inject.Hook{
+		OnStart: func(_ context.Context) error {
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return err
+			}
+			go grpcServer.Serve(listener)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stopped := make(chan struct{})
+			go func() {
+				grpcServer.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-ctx.Done():
+				grpcServer.Stop()
+			}
+			return nil
+		},
+	})
 	return grpcServer, WeatherPrediction{}
 }
 
 func WeatherPredictionServerModule() inject.Module {
 	return inject.CombineModules(
 		weatherPredictionServerModule{},
+		constant.ConstantModule(":80", WeatherPrediction{}),
 		constant.ConstantModule("ai-service:80", ai.AiService{}),
 		constant.ConstantModule("blockchain-service:80", blockchain.BlockchainService{}),
-		constant.ConstantModule(true, blockchain.BlockchainService{}),
 		autoinject.AutoInjectModule(new(*Server)),
 	)
 }
 
 func main() {
-	injector, _ := inject.InjectorOf(
+	app.ApplicationOf(
 		grpcinject.GrpcServerModule{},
 		grpcinject.GrpcClientModule(ai.AiService{}),
 		grpcinject.GrpcClientModule(blockchain.BlockchainService{}),
 		ai.AiServiceClientModule(),
-		blockchain.BlockchainServiceClientModule(),
+		blockchain.DevelBlockchainServiceClientModule(),
 		WeatherPredictionServerModule(),
-	)
-	server := injector.MustGet(
-		new(*grpc.Server), WeatherPrediction{},
-	).(*grpc.Server)
-	listener, _ := net.Listen("tcp", ":80")
-	server.Serve(listener)
+	).
+		WithRoot(new(*grpc.Server), WeatherPrediction{}).
+		Main()
 }
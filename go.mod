@@ -0,0 +1,23 @@
+module github.com/monnoroch/go-inject
+
+go 1.22.0
+
+require (
+	github.com/golang/protobuf v1.5.4
+	github.com/stretchr/testify v1.8.4
+	golang.org/x/net v0.35.0
+	golang.org/x/tools v0.30.0
+	google.golang.org/grpc v1.64.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+	google.golang.org/protobuf v1.33.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
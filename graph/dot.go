@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Get the Graphviz DOT representation of the graph.
+// Nodes of modules are in clusters labeled with modules' types. Nodes of cached providers have
+// double borders, nodes of scoped providers have bold borders, nodes of providers that can return
+// errors are red, nodes of sets and maps are three-dimensional boxes, nodes of built-in values are
+// rounded and nodes of dependencies without providers are dashed. Lazy dependencies are dashed.
+func (self Graph) DOT() string {
+	ids := nodeIds(self.Nodes)
+	builder := strings.Builder{}
+	builder.WriteString("digraph inject {\n")
+	builder.WriteString("\tnode [shape=box];\n")
+	modules, nodes := self.modules()
+	for index, module := range modules {
+		indent := "\t"
+		if module != "" {
+			fmt.Fprintf(&builder, "\tsubgraph cluster_%d {\n", index)
+			fmt.Fprintf(&builder, "\t\tlabel=\"%s\";\n", dotEscape(module))
+			indent = "\t\t"
+		}
+		for _, node := range nodes[module] {
+			fmt.Fprintf(&builder, "%s%s [%s];\n", indent, ids[node.ID], strings.Join(dotAttributes(node), ", "))
+		}
+		if module != "" {
+			builder.WriteString("\t}\n")
+		}
+	}
+	for _, edge := range self.Edges {
+		fmt.Fprintf(&builder, "\t%s -> %s", ids[edge.From], ids[edge.To])
+		if edge.Lazy {
+			builder.WriteString(" [style=dashed]")
+		}
+		builder.WriteString(";\n")
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+func dotAttributes(node Node) []string {
+	attributes := []string{
+		fmt.Sprintf("label=\"%s\\n%s\"", dotEscape(node.Type), dotEscape(node.Annotation)),
+	}
+	if node.Cached {
+		attributes = append(attributes, "peripheries=2")
+	}
+	if node.Scoped {
+		attributes = append(attributes, "style=bold")
+	}
+	if node.ReturnsError {
+		attributes = append(attributes, "color=red")
+	}
+	if node.Set {
+		attributes = append(attributes, "shape=box3d")
+	}
+	if node.Builtin {
+		attributes = append(attributes, "style=rounded")
+	}
+	if node.Missing {
+		attributes = append(attributes, "style=dashed")
+	}
+	return attributes
+}
+
+func dotEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
+
+// Get short identifiers of nodes for formats that restrict characters of identifiers.
+func nodeIds(nodes []Node) map[string]string {
+	ids := map[string]string{}
+	for index, node := range nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", index)
+	}
+	return ids
+}
@@ -0,0 +1,149 @@
+// graph exports dependency graphs of go-inject modules as Graphviz DOT, Mermaid flowcharts and JSON.
+//
+// Nodes of a graph are keys: keys of provided values, built-in values and dependencies without
+// providers. Edges go from values to their dependencies and are either strict or lazy.
+// Nodes of provided values are grouped by the modules that provide them, and nodes of sets and maps
+// of elements by `inject.SetModule`.
+//
+// The JSON representation of a graph is an object with the following fields:
+//
+//	{
+//	  "nodes": [{
+//	    "id": string,          // The string representation of the key, "<type>/<annotation>".
+//	    "type": string,        // The type of the values of the key.
+//	    "annotation": string,  // The type of the annotation of the key.
+//	    "module": string,      // The type of the module that provides the key, omitted if there is none.
+//	    "cached": bool,        // Whether the provider of the key is cached.
+//	    "scoped": bool,        // Whether the provider of the key is scoped.
+//	    "returnsError": bool,  // Whether the provider of the key can return an error.
+//	    "set": bool,           // Whether the key is of a set or a map of elements of other keys.
+//	    "builtin": bool,       // Whether the key is provided by the injector itself.
+//	    "missing": bool        // Whether the key is a dependency without a provider.
+//	  }],
+//	  "edges": [{
+//	    "from": string,  // The id of the node of the dependent value.
+//	    "to": string,    // The id of the node of the dependency.
+//	    "lazy": bool     // Whether the dependency is through a lazy argument.
+//	  }]
+//	}
+//
+// Nodes are sorted by their ids and edges are sorted by their dependent nodes and then in the order
+// of the providers' arguments.
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/monnoroch/go-inject"
+)
+
+// A dependency graph of an injector.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// A node of the graph: a key and its provider.
+type Node struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	Annotation   string `json:"annotation"`
+	Module       string `json:"module,omitempty"`
+	Cached       bool   `json:"cached"`
+	Scoped       bool   `json:"scoped"`
+	ReturnsError bool   `json:"returnsError"`
+	Set          bool   `json:"set"`
+	Builtin      bool   `json:"builtin"`
+	Missing      bool   `json:"missing"`
+}
+
+// An edge of the graph: a dependency of a value.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Lazy bool   `json:"lazy"`
+}
+
+// Build the dependency graph of the injector.
+// Values that providers get from the injector injected into them are not known in advance and
+// are not included.
+func Of(injector *inject.Injector) Graph {
+	graph := Graph{Nodes: []Node{}, Edges: []Edge{}}
+	nodes := map[inject.Key]bool{}
+	addNode := func(key inject.Key) {
+		if nodes[key] {
+			return
+		}
+		nodes[key] = true
+		node := Node{
+			ID:         key.String(),
+			Type:       key.ValueType().String(),
+			Annotation: key.AnnotationType().String(),
+		}
+		if module := injector.Module(key); module != nil {
+			node.Module = fmt.Sprintf("%T", module)
+			node.Cached = injector.IsCached(key)
+			node.Scoped = injector.IsScoped(key)
+			node.ReturnsError = injector.ReturnsError(key)
+			node.Set = injector.IsSet(key)
+		} else if injector.Has(key) {
+			node.Builtin = true
+		} else {
+			node.Missing = true
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	for _, key := range injector.Keys() {
+		addNode(key)
+		edges := map[inject.Dependency]bool{}
+		for _, dependency := range injector.Dependencies(key) {
+			if edges[dependency] {
+				continue
+			}
+			edges[dependency] = true
+			addNode(dependency.Key)
+			graph.Edges = append(graph.Edges, Edge{
+				From: key.String(),
+				To:   dependency.Key.String(),
+				Lazy: dependency.Lazy,
+			})
+		}
+	}
+
+	sort.Slice(graph.Nodes, func(i int, j int) bool {
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+	return graph
+}
+
+// Build the dependency graph of an injector created from the list of modules.
+func OfModules(modules ...inject.Module) (Graph, error) {
+	injector, err := inject.InjectorOf(modules...)
+	if err != nil {
+		return Graph{}, err
+	}
+	return Of(injector), nil
+}
+
+// Get the JSON representation of the graph, as documented in the package description.
+func (self Graph) JSON() ([]byte, error) {
+	return json.MarshalIndent(self, "", "  ")
+}
+
+// Get modules of the graph's nodes, sorted, and for each of them the nodes that it provides.
+// Nodes without modules are grouped under the empty module.
+func (self Graph) modules() ([]string, map[string][]Node) {
+	modules := []string{}
+	nodes := map[string][]Node{}
+	for _, node := range self.Nodes {
+		if _, ok := nodes[node.Module]; !ok {
+			modules = append(modules, node.Module)
+		}
+		nodes[node.Module] = append(nodes[node.Module], node)
+	}
+	sort.Strings(modules)
+	return modules, nodes
+}
@@ -0,0 +1,230 @@
+package graph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/monnoroch/go-inject"
+)
+
+type GraphTests struct {
+	suite.Suite
+	graph Graph
+}
+
+type annotation1 struct{}
+type annotation2 struct{}
+
+type testModule struct{}
+
+func (self testModule) ProvideCachedBase() (int, annotation1) {
+	return 0, annotation1{}
+}
+
+type testDependentModule struct{}
+
+func (self testDependentModule) ProvideDependent(
+	value int, _ annotation1,
+	lazyValue func() int, _ annotation1,
+	injector *inject.Injector, _ inject.Builtin,
+	missing string, _ annotation2,
+) (string, annotation1, error) {
+	return "", annotation1{}, nil
+}
+
+func (self *GraphTests) SetupTest() {
+	graph, err := OfModules(testModule{}, testDependentModule{})
+	self.Require().Nil(err)
+	self.graph = graph
+}
+
+func (self *GraphTests) TestOf() {
+	self.Equal(Graph{
+		Nodes: []Node{
+			{
+				ID:         "*inject.Injector/inject.Builtin",
+				Type:       "*inject.Injector",
+				Annotation: "inject.Builtin",
+				Builtin:    true,
+			},
+			{
+				ID:         "int/graph.annotation1",
+				Type:       "int",
+				Annotation: "graph.annotation1",
+				Module:     "graph.testModule",
+				Cached:     true,
+			},
+			{
+				ID:           "string/graph.annotation1",
+				Type:         "string",
+				Annotation:   "graph.annotation1",
+				Module:       "graph.testDependentModule",
+				ReturnsError: true,
+			},
+			{
+				ID:         "string/graph.annotation2",
+				Type:       "string",
+				Annotation: "graph.annotation2",
+				Missing:    true,
+			},
+		},
+		Edges: []Edge{
+			{From: "string/graph.annotation1", To: "int/graph.annotation1"},
+			{From: "string/graph.annotation1", To: "int/graph.annotation1", Lazy: true},
+			{From: "string/graph.annotation1", To: "*inject.Injector/inject.Builtin"},
+			{From: "string/graph.annotation1", To: "string/graph.annotation2"},
+		},
+	}, self.graph)
+}
+
+func (self *GraphTests) TestOfInvalidModules() {
+	_, err := OfModules(testInvalidModule{})
+	self.NotNil(err)
+}
+
+type testInvalidModule struct{}
+
+func (self testInvalidModule) Provide() {}
+
+func (self *GraphTests) TestDOT() {
+	self.Equal(`digraph inject {
+	node [shape=box];
+	n0 [label="*inject.Injector\ninject.Builtin", style=rounded];
+	n3 [label="string\ngraph.annotation2", style=dashed];
+	subgraph cluster_1 {
+		label="graph.testDependentModule";
+		n2 [label="string\ngraph.annotation1", color=red];
+	}
+	subgraph cluster_2 {
+		label="graph.testModule";
+		n1 [label="int\ngraph.annotation1", peripheries=2];
+	}
+	n2 -> n1;
+	n2 -> n1 [style=dashed];
+	n2 -> n0;
+	n2 -> n3;
+}
+`, self.graph.DOT())
+}
+
+func (self *GraphTests) TestMermaid() {
+	self.Equal(`flowchart LR
+	n0(["*inject.Injector<br/>inject.Builtin"])
+	n3["string<br/>graph.annotation2"]
+	subgraph m1 ["graph.testDependentModule"]
+		n2["string<br/>graph.annotation1"]
+	end
+	subgraph m2 ["graph.testModule"]
+		n1["int<br/>graph.annotation1"]
+	end
+	n2 --> n1
+	n2 -.-> n1
+	n2 --> n0
+	n2 --> n3
+	classDef cached stroke-width:4px
+	classDef scoped fill:#def
+	classDef returnsError stroke:#d00
+	classDef missing stroke-dasharray:5 5
+	class n1 cached
+	class n2 returnsError
+	class n3 missing
+`, self.graph.Mermaid())
+}
+
+func (self *GraphTests) TestMermaidEscape() {
+	self.Equal("chan#lt;- #quot;int#quot;", mermaidEscape(`chan<- "int"`))
+}
+
+func (self *GraphTests) TestJSON() {
+	data, err := self.graph.JSON()
+	self.Require().Nil(err)
+	graph := Graph{}
+	self.Require().Nil(json.Unmarshal(data, &graph))
+	self.Equal(self.graph, graph)
+
+	fields := map[string][]map[string]interface{}{}
+	self.Require().Nil(json.Unmarshal(data, &fields))
+	self.Equal(map[string]interface{}{
+		"id":           "int/graph.annotation1",
+		"type":         "int",
+		"annotation":   "graph.annotation1",
+		"module":       "graph.testModule",
+		"cached":       true,
+		"scoped":       false,
+		"returnsError": false,
+		"set":          false,
+		"builtin":      false,
+		"missing":      false,
+	}, fields["nodes"][1])
+	self.Equal(map[string]interface{}{
+		"from": "string/graph.annotation1",
+		"to":   "int/graph.annotation1",
+		"lazy": true,
+	}, fields["edges"][1])
+}
+
+type testSetModule struct{}
+
+func (self testSetModule) ProvideIntoValues() (int, annotation2) {
+	return 1, annotation2{}
+}
+
+func (self testSetModule) ProvideCachedIntoValuesAgain() (int, annotation2) {
+	return 2, annotation2{}
+}
+
+func (self *GraphTests) TestSet() {
+	graph, err := OfModules(testSetModule{})
+	self.Require().Nil(err)
+	self.Equal([]Node{
+		{
+			ID:           "[]int/graph.annotation2",
+			Type:         "[]int",
+			Annotation:   "graph.annotation2",
+			Module:       "inject.SetModule",
+			ReturnsError: true,
+			Set:          true,
+		},
+		{
+			ID:         "int/graph.annotation2#0",
+			Type:       "int",
+			Annotation: "graph.annotation2",
+			Module:     "graph.testSetModule",
+			Cached:     true,
+		},
+		{
+			ID:         "int/graph.annotation2#1",
+			Type:       "int",
+			Annotation: "graph.annotation2",
+			Module:     "graph.testSetModule",
+		},
+	}, graph.Nodes)
+	self.Contains(graph.DOT(), `[label="[]int\ngraph.annotation2", color=red, shape=box3d];`)
+	self.Contains(graph.Mermaid(), `[["[]int<br/>graph.annotation2"]]`)
+}
+
+type testScopedModule struct{}
+
+func (self testScopedModule) ProvideScopedRequest() (string, annotation2) {
+	return "", annotation2{}
+}
+
+func (self *GraphTests) TestScoped() {
+	graph, err := OfModules(testScopedModule{})
+	self.Require().Nil(err)
+	self.Equal([]Node{{
+		ID:         "string/graph.annotation2",
+		Type:       "string",
+		Annotation: "graph.annotation2",
+		Module:     "graph.testScopedModule",
+		Scoped:     true,
+	}}, graph.Nodes)
+	self.Contains(graph.DOT(), `n0 [label="string\ngraph.annotation2", style=bold];`)
+	self.Contains(graph.Mermaid(), "\tclass n0 scoped\n")
+}
+
+func TestGraph(t *testing.T) {
+	suite.Run(t, new(GraphTests))
+}
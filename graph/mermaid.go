@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Get the Mermaid flowchart representation of the graph.
+// Nodes of modules are in subgraphs titled with modules' types. Nodes of cached providers have
+// the `cached` class with thick borders, nodes of scoped providers have the `scoped` class with
+// a blue fill, nodes of providers that can return errors have the `returnsError` class with red
+// borders, nodes of sets and maps have double sides, nodes of built-in values are rounded and nodes
+// of dependencies without providers have the `missing` class with dashed borders.
+// Lazy dependencies are dotted.
+func (self Graph) Mermaid() string {
+	ids := nodeIds(self.Nodes)
+	builder := strings.Builder{}
+	builder.WriteString("flowchart LR\n")
+	modules, nodes := self.modules()
+	for index, module := range modules {
+		indent := "\t"
+		if module != "" {
+			fmt.Fprintf(&builder, "\tsubgraph m%d [\"%s\"]\n", index, mermaidEscape(module))
+			indent = "\t\t"
+		}
+		for _, node := range nodes[module] {
+			label := mermaidEscape(node.Type) + "<br/>" + mermaidEscape(node.Annotation)
+			if node.Builtin {
+				fmt.Fprintf(&builder, "%s%s([\"%s\"])\n", indent, ids[node.ID], label)
+			} else if node.Set {
+				fmt.Fprintf(&builder, "%s%s[[\"%s\"]]\n", indent, ids[node.ID], label)
+			} else {
+				fmt.Fprintf(&builder, "%s%s[\"%s\"]\n", indent, ids[node.ID], label)
+			}
+		}
+		if module != "" {
+			builder.WriteString("\tend\n")
+		}
+	}
+	for _, edge := range self.Edges {
+		arrow := "-->"
+		if edge.Lazy {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&builder, "\t%s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+	}
+
+	builder.WriteString("\tclassDef cached stroke-width:4px\n")
+	builder.WriteString("\tclassDef scoped fill:#def\n")
+	builder.WriteString("\tclassDef returnsError stroke:#d00\n")
+	builder.WriteString("\tclassDef missing stroke-dasharray:5 5\n")
+	classes := []struct {
+		name string
+		has  func(node Node) bool
+	}{
+		{"cached", func(node Node) bool { return node.Cached }},
+		{"scoped", func(node Node) bool { return node.Scoped }},
+		{"returnsError", func(node Node) bool { return node.ReturnsError }},
+		{"missing", func(node Node) bool { return node.Missing }},
+	}
+	for _, class := range classes {
+		classIds := []string{}
+		for _, node := range self.Nodes {
+			if class.has(node) {
+				classIds = append(classIds, ids[node.ID])
+			}
+		}
+		if len(classIds) > 0 {
+			fmt.Fprintf(&builder, "\tclass %s %s\n", strings.Join(classIds, ","), class.name)
+		}
+	}
+	return builder.String()
+}
+
+func mermaidEscape(value string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(value)
+}
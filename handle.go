@@ -0,0 +1,91 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// A handle for getting values of type T from the injector, injected as a provider argument
+// with the annotation of the values:
+//
+//	func (self Module) ProvideServer(client inject.Handle[Client], _ Backend) (*Server, Public) {
+//		return &Server{client: client}, Public{}
+//	}
+//
+// Unlike lazy arguments, handles can be stored and used at any time, including after
+// the provider returns, and from any goroutine. While the provider runs, values are got as its
+// dependencies, so dependency cycles are detected. After it returns, values are got as if they
+// were requested from the injector, with caching of cached providers.
+// Like lazy arguments, handles do not create dependency cycles.
+type Handle[T any] struct {
+	injector *Injector
+	key      Key
+}
+
+// Implemented by all handle types, for injecting them without knowing T.
+type handle interface {
+	handleValueType() reflect.Type
+	withInjector(injector *Injector, key Key) interface{}
+}
+
+var handleInterfaceType = reflect.TypeOf((*handle)(nil)).Elem()
+
+var unboundHandleError = errors.New("Getting a value from a handle that was not injected")
+
+// Get the key of values got by the handle.
+func (self Handle[T]) Key() Key {
+	return self.key
+}
+
+// Get the value from the injector.
+// While the provider that got the handle runs, the value is got with the context of the provider's
+// resolution, otherwise with the background context.
+func (self Handle[T]) Get() (T, error) {
+	if self.injector == nil {
+		var zero T
+		return zero, unboundHandleError
+	}
+	return typedValue[T](self.injector.getKey(self.key))
+}
+
+// Get the value from the injector, passing the context to providers that take it.
+func (self Handle[T]) GetContext(ctx context.Context) (T, error) {
+	if self.injector == nil {
+		var zero T
+		return zero, unboundHandleError
+	}
+	return typedValue[T](self.injector.getKeyContext(ctx, self.key))
+}
+
+// Get the value from the injector, panic if there was an error.
+func (self Handle[T]) MustGet() T {
+	value, err := self.Get()
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+func (self Handle[T]) handleValueType() reflect.Type {
+	return reflect.TypeOf(new(T)).Elem()
+}
+
+func (self Handle[T]) withInjector(injector *Injector, key Key) interface{} {
+	return Handle[T]{injector: injector, key: key}
+}
+
+// Get the type of values of the handle if the argument is a handle.
+func getHandleValueType(key Key) reflect.Type {
+	if key.valueType.Kind() != reflect.Struct || !key.valueType.Implements(handleInterfaceType) {
+		return nil
+	}
+	return reflect.Zero(key.valueType).Interface().(handle).handleValueType()
+}
+
+// Create a handle for the argument of the provider of the path's key.
+func (self *Injector) handleFor(argumentKey Key, path *dependencyPath) reflect.Value {
+	valueKey := Key{valueType: getHandleValueType(argumentKey), annotationType: argumentKey.annotationType}
+	value := reflect.Zero(argumentKey.valueType).Interface().(handle).withInjector(self.boundTo(path), valueKey)
+	return reflect.ValueOf(value)
+}
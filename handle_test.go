@@ -0,0 +1,135 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HandleTests struct {
+	suite.Suite
+}
+
+type handleTestComponent struct {
+	value Handle[int]
+}
+
+type handleTestModule struct {
+	calls *int
+}
+
+func (self handleTestModule) ProvideCachedValue() (int, Annotation1) {
+	*self.calls += 1
+	return testValue, Annotation1{}
+}
+
+func (self handleTestModule) ProvideComponent(value Handle[int], _ Annotation1) (*handleTestComponent, Annotation1) {
+	return &handleTestComponent{value: value}, Annotation1{}
+}
+
+func (self *HandleTests) component(injector *Injector) *handleTestComponent {
+	component, err := Get[*handleTestComponent, Annotation1](injector)
+	self.Require().Nil(err)
+	return component
+}
+
+func (self *HandleTests) TestGetAfterProviderReturns() {
+	calls := 0
+	injector, err := InjectorOf(handleTestModule{calls: &calls})
+	self.Require().Nil(err)
+	component := self.component(injector)
+	self.Equal(0, calls)
+
+	value, err := component.value.Get()
+	self.Nil(err)
+	self.Equal(testValue, value)
+	self.Equal(testValue, component.value.MustGet())
+	self.Equal(testKey(Annotation1{}), component.value.Key())
+	// The value is cached.
+	self.Equal(1, calls)
+}
+
+func (self *HandleTests) TestGetFromGoroutines() {
+	calls := 0
+	injector, err := InjectorOf(handleTestModule{calls: &calls})
+	self.Require().Nil(err)
+	component := self.component(injector)
+
+	var group sync.WaitGroup
+	for index := 0; index < 10; index += 1 {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			value, err := component.value.Get()
+			self.Nil(err)
+			self.Equal(testValue, value)
+		}()
+	}
+	group.Wait()
+	self.Equal(1, calls)
+}
+
+func (self *HandleTests) TestGetContext() {
+	calls := 0
+	injector, err := InjectorOf(handleTestModule{calls: &calls})
+	self.Require().Nil(err)
+	component := self.component(injector)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = component.value.GetContext(ctx)
+	self.True(errors.Is(err, context.Canceled))
+	self.Equal(0, calls)
+}
+
+func (self *HandleTests) TestGetClosed() {
+	calls := 0
+	injector, err := InjectorOf(handleTestModule{calls: &calls})
+	self.Require().Nil(err)
+	component := self.component(injector)
+	self.Require().Nil(injector.Close(context.Background()))
+
+	_, err = component.value.Get()
+	self.Equal(injectorClosedError, err)
+}
+
+func (self *HandleTests) TestNotInjected() {
+	_, err := Handle[int]{}.Get()
+	self.Equal(unboundHandleError, err)
+	_, err = Handle[int]{}.GetContext(context.Background())
+	self.Equal(unboundHandleError, err)
+}
+
+type handleTestCycleModule struct{}
+
+func (self handleTestCycleModule) ProvideValue(value Handle[int], _ Annotation2) (int, Annotation1, error) {
+	result, err := value.Get()
+	return result, Annotation1{}, err
+}
+
+func (self handleTestCycleModule) ProvideDependent(value int, _ Annotation1) (int, Annotation2) {
+	return value, Annotation2{}
+}
+
+func (self *HandleTests) TestCycleWhileProviding() {
+	// Handles do not create dependency cycles, so the injector is valid.
+	injector, err := InjectorOf(handleTestCycleModule{})
+	self.Require().Nil(err)
+	self.Equal([]Dependency{{Key: testKey(Annotation2{}), Lazy: true}}, injector.Dependencies(testKey(Annotation1{})))
+
+	_, err = injector.Get(new(int), Annotation1{})
+	var cycleErr CycleError
+	self.Require().True(errors.As(err, &cycleErr))
+	self.Equal([]CycleStep{
+		{Key: testKey(Annotation1{})},
+		{Key: testKey(Annotation2{}), Lazy: true},
+		{Key: testKey(Annotation1{})},
+	}, cycleErr.Steps)
+}
+
+func TestHandle(t *testing.T) {
+	suite.Run(t, new(HandleTests))
+}
@@ -0,0 +1,185 @@
+package inject
+
+import (
+	"context"
+	"runtime"
+	"sort"
+)
+
+// Provide values of all cached providers, so that errors in them are reported at startup instead of
+// when the values are first needed.
+// Independent values are provided in parallel, by at most `parallelism` providers at a time, or
+// by `runtime.GOMAXPROCS(0)` providers if `parallelism` is not positive. A value is only provided
+// after the values of cached providers it strictly depends on, directly or through not cached
+// providers. Lazy dependencies are not provided unless providers call them.
+// Values that depend on values that failed to be provided are not provided.
+// Providers that take a context get the passed one. If the context is done, values that have not
+// started being provided are not provided.
+// Returns errors of all values that failed to be provided.
+func (self *Injector) InitAll(ctx context.Context, parallelism int) error {
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	dependencies := cachedDependencies(self.providers)
+	dependents := map[Key][]Key{}
+	remainingDependencies := map[Key]int{}
+	ready := []Key{}
+	for key, keyDependencies := range dependencies {
+		remainingDependencies[key] = len(keyDependencies)
+		for _, dependency := range keyDependencies {
+			dependents[dependency] = append(dependents[dependency], key)
+		}
+		if len(keyDependencies) == 0 {
+			ready = append(ready, key)
+		}
+	}
+	// Start providing values in a deterministic order.
+	sortKeys(ready)
+
+	type result struct {
+		key Key
+		err error
+		// Whether the value was not provided because the context is done.
+		canceled bool
+	}
+	results := make(chan result)
+	semaphore := make(chan struct{}, parallelism)
+	provide := func(key Key) {
+		semaphore <- struct{}{}
+		defer func() { <-semaphore }()
+		if ctx.Err() != nil {
+			results <- result{key: key, canceled: true}
+			return
+		}
+		_, err := self.getCached((*dependencyPath)(nil).childWithContext(ctx, key, false))
+		results <- result{key: key, err: err, canceled: err != nil && ctx.Err() != nil}
+	}
+
+	// Keys that are not going to be provided because their dependencies failed.
+	skipped := map[Key]bool{}
+	var skip func(key Key)
+	skip = func(key Key) {
+		for _, dependent := range dependents[key] {
+			if !skipped[dependent] {
+				skipped[dependent] = true
+				skip(dependent)
+			}
+		}
+	}
+
+	errs := map[Key]error{}
+	canceled := false
+	done := 0
+	running := 0
+	for {
+		for _, key := range ready {
+			running += 1
+			go provide(key)
+		}
+		ready = ready[:0]
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running -= 1
+		done += 1
+		if result.canceled || result.err != nil {
+			if result.canceled {
+				canceled = true
+			} else {
+				errs[result.key] = result.err
+			}
+			skip(result.key)
+			continue
+		}
+		for _, dependent := range dependents[result.key] {
+			remainingDependencies[dependent] -= 1
+			if remainingDependencies[dependent] == 0 && !skipped[dependent] {
+				ready = append(ready, dependent)
+			}
+		}
+		sortKeys(ready)
+	}
+
+	if done+len(skipped) < len(dependencies) {
+		// Some values were never ready, which only happens if they depend on each other.
+		return checkCycles(self.providers)
+	}
+	return sortedErrors(errs, canceled, ctx)
+}
+
+func sortedErrors(errs map[Key]error, canceled bool, ctx context.Context) error {
+	keys := make([]Key, 0, len(errs))
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+
+	result := errorList{}
+	for _, key := range keys {
+		result = append(result, errs[key])
+	}
+	if canceled {
+		result = append(result, ctx.Err())
+	}
+	return result.asError()
+}
+
+// Get cached providers' keys, mapped to keys of cached providers that they depend on.
+// Strict dependencies on not cached providers are replaced by those providers' cached dependencies.
+func cachedDependencies(providers *providersData) map[Key][]Key {
+	// Cached dependencies of each provider, including not cached ones.
+	memo := map[Key][]Key{}
+	var dependenciesOf func(key Key, visiting map[Key]bool) []Key
+	dependenciesOf = func(key Key, visiting map[Key]bool) []Key {
+		if dependencies, ok := memo[key]; ok {
+			return dependencies
+		}
+		// Guard against cycles, which are reported when the values are provided.
+		if visiting[key] {
+			return nil
+		}
+		visiting[key] = true
+		defer delete(visiting, key)
+
+		unique := map[Key]bool{}
+		dependencies := []Key{}
+		for _, argumentKey := range providers.providers[key].arguments {
+			if getLazyArgumentType(argumentKey) != nil {
+				continue
+			}
+			argument, ok := providers.providers[argumentKey]
+			if !ok {
+				continue
+			}
+			argumentDependencies := []Key{argumentKey}
+			if !argument.cached {
+				argumentDependencies = dependenciesOf(argumentKey, visiting)
+			}
+			for _, dependency := range argumentDependencies {
+				if !unique[dependency] {
+					unique[dependency] = true
+					dependencies = append(dependencies, dependency)
+				}
+			}
+		}
+		memo[key] = dependencies
+		return dependencies
+	}
+
+	result := map[Key][]Key{}
+	for key, provider := range providers.providers {
+		if provider.cached {
+			result[key] = dependenciesOf(key, map[Key]bool{})
+		}
+	}
+	return result
+}
+
+func sortKeys(keys []Key) {
+	sort.Slice(keys, func(i int, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+}
@@ -0,0 +1,211 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type InitAllTests struct {
+	suite.Suite
+}
+
+type initTestModule struct {
+	lock   *sync.Mutex
+	events *[]string
+}
+
+func (self initTestModule) event(name string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	*self.events = append(*self.events, name)
+}
+
+func (self initTestModule) ProvideCachedBase() (int, Annotation1) {
+	self.event("base")
+	return testValue, Annotation1{}
+}
+
+func (self initTestModule) ProvideNotCached(value int, _ Annotation1) (int, Annotation2) {
+	self.event("not cached")
+	return value + 1, Annotation2{}
+}
+
+func (self initTestModule) ProvideCachedDependent(value int, _ Annotation2) (int, Annotation3) {
+	self.event("dependent")
+	return value + 1, Annotation3{}
+}
+
+func (self initTestModule) ProvideCachedLazy(value func() int, _ Annotation4) (string, Annotation1) {
+	self.event("lazy")
+	return "lazy", Annotation1{}
+}
+
+func (self initTestModule) ProvideUnused() (int, Annotation4) {
+	self.event("unused")
+	return testValue, Annotation4{}
+}
+
+func newInitTestModule() initTestModule {
+	return initTestModule{lock: &sync.Mutex{}, events: &[]string{}}
+}
+
+func (self *InitAllTests) TestInitAll() {
+	module := newInitTestModule()
+	injector, err := InjectorOf(module)
+	self.Require().Nil(err)
+
+	self.Require().Nil(injector.InitAll(context.Background(), 1))
+	self.ElementsMatch([]string{"base", "not cached", "dependent", "lazy"}, *module.events)
+	self.True(indexOf(*module.events, "base") < indexOf(*module.events, "dependent"))
+
+	self.Equal(testValue+2, injector.MustGet(new(int), Annotation3{}))
+	self.Len(*module.events, 4)
+}
+
+func (self *InitAllTests) TestInitAllAfterGet() {
+	module := newInitTestModule()
+	injector, err := InjectorOf(module)
+	self.Require().Nil(err)
+	_ = injector.MustGet(new(int), Annotation1{})
+
+	self.Require().Nil(injector.InitAll(context.Background(), 0))
+	self.Equal(1, countEvents(*module.events, "base"))
+}
+
+type initTestParallelModule struct {
+	started    *sync.WaitGroup
+	running    *int32
+	maxRunning *int32
+}
+
+func (self initTestParallelModule) provide() error {
+	running := atomic.AddInt32(self.running, 1)
+	defer atomic.AddInt32(self.running, -1)
+	for {
+		maxRunning := atomic.LoadInt32(self.maxRunning)
+		if running <= maxRunning || atomic.CompareAndSwapInt32(self.maxRunning, maxRunning, running) {
+			break
+		}
+	}
+
+	if self.started == nil {
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+	// Wait for the other provider to start, which only happens if they run in parallel.
+	self.started.Done()
+	done := make(chan struct{})
+	go func() {
+		self.started.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(time.Second):
+		return errors.New("providers did not run in parallel")
+	}
+}
+
+func (self initTestParallelModule) ProvideCachedFirst() (int, Annotation1, error) {
+	return testValue, Annotation1{}, self.provide()
+}
+
+func (self initTestParallelModule) ProvideCachedSecond() (int, Annotation2, error) {
+	return testValue, Annotation2{}, self.provide()
+}
+
+func (self initTestParallelModule) ProvideCachedThird() (int, Annotation3, error) {
+	return testValue, Annotation3{}, nil
+}
+
+func (self *InitAllTests) TestInitAllParallel() {
+	started := &sync.WaitGroup{}
+	started.Add(2)
+	injector, err := InjectorOf(initTestParallelModule{
+		started:    started,
+		running:    new(int32),
+		maxRunning: new(int32),
+	})
+	self.Require().Nil(err)
+	self.Nil(injector.InitAll(context.Background(), 2))
+}
+
+func (self *InitAllTests) TestInitAllParallelismLimit() {
+	module := initTestParallelModule{running: new(int32), maxRunning: new(int32)}
+	injector, err := InjectorOf(module)
+	self.Require().Nil(err)
+	self.Require().Nil(injector.InitAll(context.Background(), 1))
+	self.Equal(int32(1), *module.maxRunning)
+}
+
+type initTestErrorsModule struct {
+	events *[]string
+}
+
+func (self initTestErrorsModule) ProvideCachedFirst() (int, Annotation1, error) {
+	return 0, Annotation1{}, testError
+}
+
+func (self initTestErrorsModule) ProvideCachedSecond() (int, Annotation2, error) {
+	return 0, Annotation2{}, testError
+}
+
+func (self initTestErrorsModule) ProvideCachedDependent(value int, _ Annotation1) (int, Annotation3) {
+	*self.events = append(*self.events, "dependent")
+	return value, Annotation3{}
+}
+
+func (self *InitAllTests) TestInitAllErrors() {
+	module := initTestErrorsModule{events: &[]string{}}
+	injector, err := InjectorOf(module)
+	self.Require().Nil(err)
+
+	err = injector.InitAll(context.Background(), 0)
+	self.Require().NotNil(err)
+	self.Equal(errorList{
+		ProviderFailedError{Key: testKey(Annotation1{}), Path: []Key{testKey(Annotation1{})}, Cause: testError},
+		ProviderFailedError{Key: testKey(Annotation2{}), Path: []Key{testKey(Annotation2{})}, Cause: testError},
+	}, err)
+	self.Equal([]string{}, *module.events)
+}
+
+func (self *InitAllTests) TestInitAllCanceled() {
+	module := newInitTestModule()
+	injector, err := InjectorOf(module)
+	self.Require().Nil(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	self.Equal(context.Canceled, injector.InitAll(ctx, 0))
+	self.Equal([]string{}, *module.events)
+}
+
+func indexOf(events []string, event string) int {
+	for index, element := range events {
+		if element == event {
+			return index
+		}
+	}
+	return -1
+}
+
+func countEvents(events []string, event string) int {
+	count := 0
+	for _, element := range events {
+		if element == event {
+			count += 1
+		}
+	}
+	return count
+}
+
+func TestInitAll(t *testing.T) {
+	suite.Run(t, new(InitAllTests))
+}
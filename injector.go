@@ -1,36 +1,65 @@
 package inject
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"reflect"
+	"runtime/debug"
 	"strings"
-	"sync"
 )
 
 // Injector is a component for providing values exported by modules.
+// It is safe to get values from multiple goroutines concurrently.
 type Injector struct {
 	providers *providersData
-	cacheLock sync.Mutex
-	cache     map[providerKey]valueErrorPair
+	cache     *valuesCache
+	lifecycle *lifecycleHooks
+	options   Options
+	// For injectors injected into providers, the path to the provided key.
+	path *dependencyPath
+	// For child injectors, the parent injector and the keys that it provides for the child.
+	parent    *Injector
+	inherited map[Key]bool
+	// For scopes, the cache of values of scoped providers.
+	scope *valuesCache
 }
 
-type valueErrorPair struct {
-	value interface{}
-	err   error
+// Options of an injector.
+type Options struct {
+	// Recover panics of providers and return them as `ProviderPanicError`s.
+	// By default panics are not recovered and crash the program.
+	RecoverPanics bool
 }
 
 // Create an injector from the list of modules.
 func InjectorOf(modules ...Module) (*Injector, error) {
+	return InjectorWithOptions(Options{}, modules...)
+}
+
+// Create an injector with the options from the list of modules.
+func InjectorWithOptions(options Options, modules ...Module) (*Injector, error) {
 	providers, err := buildProviders(CombineModules(modules...))
 	if err != nil {
 		return nil, err
 	}
+	if err := checkCycles(providers); err != nil {
+		return nil, err
+	}
+	if err := checkScopes(providers); err != nil {
+		return nil, err
+	}
+
+	injector := newInjector(providers)
+	injector.options = options
+	return injector, nil
+}
 
+func newInjector(providers *providersData) *Injector {
 	return &Injector{
 		providers: providers,
-		cache:     map[providerKey]valueErrorPair{},
-	}, nil
+		cache:     &valuesCache{},
+		lifecycle: &lifecycleHooks{},
+	}
 }
 
 // Get the annotated value from the injector, panic if there was an error.
@@ -43,35 +72,63 @@ func (self *Injector) MustGet(pointerToType interface{}, annotation Annotation)
 }
 
 // Get the annotated value from the injector.
+// Can be called from providers that depend on the injector: the value is then provided
+// as a dependency of the provider's value, with the context of the provider's resolution.
 func (self *Injector) Get(pointerToType interface{}, annotation Annotation) (interface{}, error) {
-	valueType := reflect.TypeOf(pointerToType).Elem()
-	annotationType := reflect.TypeOf(annotation)
-	key := providerKey{
-		valueType:      valueType,
-		annotationType: annotationType,
+	return self.getKey(KeyOf(pointerToType, annotation))
+}
+
+// Get the value of the key, see `Get`.
+func (self *Injector) getKey(key Key) (interface{}, error) {
+	if self.path == nil || self.path.isFinished() {
+		return self.getCached((*dependencyPath)(nil).child(key, false))
 	}
-	return self.getLocked(key)
+	// Values requested by providers are similar to lazy dependencies.
+	return self.getCached(self.path.child(key, true))
 }
 
-func (self *Injector) getLocked(key providerKey) (interface{}, error) {
-	self.cacheLock.Lock()
-	defer self.cacheLock.Unlock()
-	return self.getCached(key)
+// Get the annotated value from the injector, passing the context to providers that take it.
+// When the context is done, providers that have not been called yet are not called and
+// the context's error is returned.
+// Cached values are provided with the context of the first call that requests them, and
+// are not cached if the context is done before they are provided.
+func (self *Injector) GetContext(
+	ctx context.Context,
+	pointerToType interface{},
+	annotation Annotation,
+) (interface{}, error) {
+	return self.getKeyContext(ctx, KeyOf(pointerToType, annotation))
 }
 
-func (self *Injector) getCached(key providerKey) (providedValue interface{}, err error) {
-	if provider, ok := self.providers.providers[key]; ok && provider.cached {
-		if value, ok := self.cache[key]; ok {
-			return value.value, value.err
-		}
-		defer func() {
-			self.cache[key] = valueErrorPair{
-				value: providedValue,
-				err:   err,
-			}
-		}()
+// Get the value of the key with the context, see `GetContext`.
+func (self *Injector) getKeyContext(ctx context.Context, key Key) (interface{}, error) {
+	if self.path == nil || self.path.isFinished() {
+		return self.getCached((*dependencyPath)(nil).childWithContext(ctx, key, false))
 	}
-	return self.get(key)
+	return self.getCached(self.path.childWithContext(ctx, key, true))
+}
+
+func (self *Injector) getCached(path *dependencyPath) (interface{}, error) {
+	if self.cache.isClosed() || self.scope != nil && self.scope.isClosed() {
+		return nil, injectorClosedError
+	}
+	if err := path.cycle(); err != nil {
+		return nil, err
+	}
+	if self.providers.providers[path.key].scoped {
+		return self.getScoped(path)
+	}
+	if self.inherited[path.key] {
+		return self.parent.getCached(path)
+	}
+
+	if isBuiltin(path.key) {
+		return self.getBuiltin(path), nil
+	}
+	if provider, ok := self.providers.providers[path.key]; ok && provider.cached {
+		return self.cache.get(path, self.withoutScope().get)
+	}
+	return self.get(path)
 }
 
 type lazyProviderError struct {
@@ -80,43 +137,78 @@ type lazyProviderError struct {
 
 var injectOutsideInjectorCallError = errors.New("Trying to call a lazy provider outside of an Injector.Get call")
 
-func (self *Injector) get(key providerKey) (interface{}, error) {
+func (self *Injector) get(path *dependencyPath) (interface{}, error) {
+	key := path.key
 	provider, ok := self.providers.providers[key]
 	if !ok {
-		return nil, provideError{key: key, cause: errors.New("No provider found")}
+		return nil, MissingProviderError{
+			Key:         key,
+			Path:        path.keys(),
+			Suggestions: self.providers.suggestions(key),
+		}
 	}
 
-	arguments := make([]reflect.Value, len(provider.arguments)*2)
-	injectionTime := true
+	defer path.finish()
+	if err := path.ctx.Err(); err != nil {
+		return nil, CanceledError{Key: key, Path: path.keys(), Cause: err}
+	}
+	firstArgument := 0
+	if provider.hasContext {
+		firstArgument = 1
+	}
+	arguments := make([]reflect.Value, firstArgument+len(provider.arguments)*2)
+	if provider.hasContext {
+		arguments[0] = reflect.ValueOf(path.ctx)
+	}
 	for index, argumentKey := range provider.arguments {
-		offset := index * 2
-		if lazyArgumentType := getLazyArgumentType(argumentKey); lazyArgumentType != nil {
-			strictArgumentKey := providerKey{valueType: lazyArgumentType, annotationType: argumentKey.annotationType}
+		offset := firstArgument + index*2
+		if getHandleValueType(argumentKey) != nil {
+			arguments[offset] = self.handleFor(argumentKey, path)
+		} else if lazyArgumentType := getLazyArgumentType(argumentKey); lazyArgumentType != nil {
+			strictArgumentKey := Key{valueType: lazyArgumentType, annotationType: argumentKey.annotationType}
+			returnsError := lazyArgumentReturnsError(argumentKey)
 			arguments[offset] = reflect.MakeFunc(argumentKey.valueType, func(_ []reflect.Value) []reflect.Value {
-				if !injectionTime {
-					panic(injectOutsideInjectorCallError)
+				if path.isFinished() {
+					if !returnsError {
+						panic(injectOutsideInjectorCallError)
+					}
+					return []reflect.Value{
+						reflect.Zero(lazyArgumentType),
+						reflect.ValueOf(&injectOutsideInjectorCallError).Elem(),
+					}
 				}
 
-				result, err := self.getCached(strictArgumentKey)
+				result, err := self.getCached(path.child(strictArgumentKey, true))
+				if !returnsError {
+					if err != nil {
+						panic(lazyProviderError{cause: err})
+					}
+					return []reflect.Value{getValueForArgument(result, lazyArgumentType)}
+				}
 				if err != nil {
-					panic(lazyProviderError{cause: err})
+					return []reflect.Value{reflect.Zero(lazyArgumentType), reflect.ValueOf(&err).Elem()}
 				}
-				return []reflect.Value{reflect.ValueOf(result)}
+				return []reflect.Value{getValueForArgument(result, lazyArgumentType), reflect.Zero(globalErrorType)}
 			})
 		} else {
-			argument, err := self.getCached(argumentKey)
+			argument, err := self.getCached(path.child(argumentKey, false))
 			if err != nil {
-				return nil, provideError{key: key, cause: err}
+				// The error already has the resolution path through this key.
+				return nil, err
 			}
 			arguments[offset] = getValueForArgument(argument, argumentKey.valueType)
 		}
 		arguments[offset+1] = reflect.Zero(argumentKey.annotationType)
 	}
 
-	outputs, err := callProviderHandlingLazyErrors(provider.provider, arguments)
-	injectionTime = false
+	// Resolving arguments can take long, the context could be done by now.
+	if err := path.ctx.Err(); err != nil {
+		return nil, CanceledError{Key: key, Path: path.keys(), Cause: err}
+	}
+	outputs, err := self.callProvider(path, provider.provider, arguments)
 	if err != nil {
-		return nil, provideError{key: key, cause: err}
+		// Errors of lazy arguments already have the resolution path through this key.
+		return nil, err
 	}
 
 	output := outputs[0].Interface()
@@ -125,13 +217,16 @@ func (self *Injector) get(key providerKey) (interface{}, error) {
 	}
 
 	if err := outputs[2].Interface(); err != nil {
-		return output, provideError{key: key, cause: err.(error)}
+		return output, ProviderFailedError{Key: key, Path: path.keys(), Cause: err.(error)}
 	} else {
 		return output, nil
 	}
 }
 
-func callProviderHandlingLazyErrors(
+// Call the provider of the path's key, returning errors of its lazy arguments and, if the injector
+// recovers panics, its panics as errors.
+func (self *Injector) callProvider(
+	path *dependencyPath,
 	provider reflect.Value,
 	arguments []reflect.Value,
 ) (result []reflect.Value, resultingErr error) {
@@ -139,6 +234,8 @@ func callProviderHandlingLazyErrors(
 		if err := recover(); err != nil {
 			if lazyProviderErr, ok := err.(lazyProviderError); ok {
 				resultingErr = lazyProviderErr.cause
+			} else if self.options.RecoverPanics {
+				resultingErr = ProviderPanicError{Key: path.key, Path: path.keys(), Value: err, Stack: debug.Stack()}
 			} else {
 				panic(err)
 			}
@@ -147,7 +244,12 @@ func callProviderHandlingLazyErrors(
 	return provider.Call(arguments), nil
 }
 
-func getLazyArgumentType(key providerKey) reflect.Type {
+// Get the type of the lazily provided value if the argument is lazy: a function returning it,
+// optionally with an error, or a handle.
+func getLazyArgumentType(key Key) reflect.Type {
+	if handleValueType := getHandleValueType(key); handleValueType != nil {
+		return handleValueType
+	}
 	if key.valueType.Kind() != reflect.Func {
 		return nil
 	}
@@ -156,10 +258,23 @@ func getLazyArgumentType(key providerKey) reflect.Type {
 	if !strings.HasPrefix(key.valueType.String(), "func() ") {
 		return nil
 	}
-	if key.valueType.NumOut() != 1 {
+	switch key.valueType.NumOut() {
+	case 1:
+		return key.valueType.Out(0)
+	case 2:
+		if key.valueType.Out(1) != globalErrorType {
+			return nil
+		}
+		return key.valueType.Out(0)
+	default:
 		return nil
 	}
-	return key.valueType.Out(0)
+}
+
+// Test if the lazy argument returns the resolution error instead of panicking with it,
+// as `func() (T, error)`.
+func lazyArgumentReturnsError(key Key) bool {
+	return getLazyArgumentType(key) != nil && key.valueType.Kind() == reflect.Func && key.valueType.NumOut() == 2
 }
 
 func getValueForArgument(argument interface{}, valueType reflect.Type) reflect.Value {
@@ -170,13 +285,3 @@ func getValueForArgument(argument interface{}, valueType reflect.Type) reflect.V
 	}
 	return reflect.ValueOf(argument)
 }
-
-type provideError struct {
-	key   providerKey
-	cause error
-}
-
-func (self provideError) Error() string {
-	return fmt.Sprintf("error providing type %v with annotation %v: %s",
-		self.key.valueType, self.key.annotationType, self.cause.Error())
-}
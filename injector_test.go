@@ -1,8 +1,11 @@
 package inject
 
 import (
+	"context"
 	"errors"
 	"reflect"
+	"runtime"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -23,17 +26,16 @@ type InjectorTests struct {
 
 func (self *InjectorTests) TestNotFound() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{},
+		providers: map[Key]providerData{},
 	})
 	_, err := self.injector.Get((*int)(nil), Annotation1{})
-	cause := err.(provideError).cause
-	self.Require().NotNil(cause)
-	self.Contains(cause.Error(), "No provider found")
+	self.Equal(MissingProviderError{Key: testKey(Annotation1{}), Path: []Key{testKey(Annotation1{})}}, err)
+	self.Contains(err.Error(), "No provider found")
 }
 
 func (self *InjectorTests) TestNotFoundTransitive() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -41,7 +43,7 @@ func (self *InjectorTests) TestNotFoundTransitive() {
 				provider: reflect.ValueOf(func(_ int, _ Annotation2) (int, Annotation1) {
 					return testValue, Annotation1{}
 				}),
-				arguments: []providerKey{{
+				arguments: []Key{{
 					valueType:      reflect.TypeOf(int(0)),
 					annotationType: reflect.TypeOf(Annotation2{}),
 				}},
@@ -49,15 +51,17 @@ func (self *InjectorTests) TestNotFoundTransitive() {
 			},
 		},
 	})
-	_, err := self.injector.Get((*int)(nil), Annotation2{})
-	cause := err.(provideError).cause
-	self.Require().NotNil(cause)
-	self.Contains(cause.Error(), "No provider found")
+	_, err := self.injector.Get((*int)(nil), Annotation1{})
+	self.Equal(MissingProviderError{
+		Key:         testKey(Annotation2{}),
+		Path:        []Key{testKey(Annotation1{}), testKey(Annotation2{})},
+		Suggestions: []Suggestion{{Key: testKey(Annotation1{}), Reason: "different annotation"}},
+	}, err)
 }
 
 func (self *InjectorTests) TestGet() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -65,7 +69,7 @@ func (self *InjectorTests) TestGet() {
 				provider: reflect.ValueOf(func() (int, Annotation1) {
 					return testValue, Annotation1{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 		},
@@ -76,7 +80,7 @@ func (self *InjectorTests) TestGet() {
 
 func (self *InjectorTests) TestGetNil() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf((*int)(nil)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -84,7 +88,7 @@ func (self *InjectorTests) TestGetNil() {
 				provider: reflect.ValueOf(func() (*int, Annotation1) {
 					return nil, Annotation1{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 		},
@@ -95,7 +99,7 @@ func (self *InjectorTests) TestGetNil() {
 
 func (self *InjectorTests) TestErrorGet() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -103,7 +107,7 @@ func (self *InjectorTests) TestErrorGet() {
 				provider: reflect.ValueOf(func() (int, Annotation1, error) {
 					return testValue, Annotation1{}, nil
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  true,
 			},
 		},
@@ -114,7 +118,7 @@ func (self *InjectorTests) TestErrorGet() {
 
 func (self *InjectorTests) TestGetError() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -122,18 +126,22 @@ func (self *InjectorTests) TestGetError() {
 				provider: reflect.ValueOf(func() (int, Annotation1, error) {
 					return testValue, Annotation1{}, testError
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  true,
 			},
 		},
 	})
 	_, err := self.injector.Get((*int)(nil), Annotation1{})
-	self.Equal(testError, err.(provideError).cause)
+	self.Equal(ProviderFailedError{
+		Key:   testKey(Annotation1{}),
+		Path:  []Key{testKey(Annotation1{})},
+		Cause: testError,
+	}, err)
 }
 
 func (self *InjectorTests) TestPanic() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -141,7 +149,7 @@ func (self *InjectorTests) TestPanic() {
 				provider: reflect.ValueOf(func() (int, Annotation1) {
 					panic(testError)
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 		},
@@ -154,7 +162,7 @@ func (self *InjectorTests) TestPanic() {
 func (self *InjectorTests) TestCachedGet() {
 	counter := testValue
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -165,7 +173,7 @@ func (self *InjectorTests) TestCachedGet() {
 					}()
 					return counter, Annotation1{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 				cached:    true,
 			},
@@ -177,7 +185,7 @@ func (self *InjectorTests) TestCachedGet() {
 
 func (self *InjectorTests) TestGetTransitiveError() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -185,7 +193,7 @@ func (self *InjectorTests) TestGetTransitiveError() {
 				provider: reflect.ValueOf(func() (int, Annotation1, error) {
 					return testValue, Annotation1{}, testError
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  true,
 			},
 			{
@@ -195,7 +203,7 @@ func (self *InjectorTests) TestGetTransitiveError() {
 				provider: reflect.ValueOf(func(value int, _ Annotation1) (int, Annotation2) {
 					return value * 2, Annotation2{}
 				}),
-				arguments: []providerKey{{
+				arguments: []Key{{
 					valueType:      reflect.TypeOf(int(0)),
 					annotationType: reflect.TypeOf(Annotation1{}),
 				}},
@@ -204,13 +212,18 @@ func (self *InjectorTests) TestGetTransitiveError() {
 		},
 	})
 	_, err := self.injector.Get((*int)(nil), Annotation2{})
-	self.Equal(testError, err.(provideError).cause.(provideError).cause)
+	self.Equal(ProviderFailedError{
+		Key:   testKey(Annotation1{}),
+		Path:  []Key{testKey(Annotation2{}), testKey(Annotation1{})},
+		Cause: testError,
+	}, err)
+	self.True(errors.Is(err, testError))
 }
 
 func (self *InjectorTests) TestGetRecalculates() {
 	counter := testValue
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -221,7 +234,7 @@ func (self *InjectorTests) TestGetRecalculates() {
 					}()
 					return counter, Annotation1{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 		},
@@ -233,7 +246,7 @@ func (self *InjectorTests) TestGetRecalculates() {
 
 func (self *InjectorTests) TestGetLazy() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -241,7 +254,7 @@ func (self *InjectorTests) TestGetLazy() {
 				provider: reflect.ValueOf(func() (int, Annotation1) {
 					return testValue, Annotation1{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 			{
@@ -251,7 +264,7 @@ func (self *InjectorTests) TestGetLazy() {
 				provider: reflect.ValueOf(func(value func() int, _ Annotation1) (int, Annotation2) {
 					return value(), Annotation2{}
 				}),
-				arguments: []providerKey{{
+				arguments: []Key{{
 					valueType:      reflect.TypeOf(func() int { return 0 }),
 					annotationType: reflect.TypeOf(Annotation1{}),
 				}},
@@ -265,7 +278,7 @@ func (self *InjectorTests) TestGetLazy() {
 
 func (self *InjectorTests) TestGetLazyNil() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf((*int)(nil)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -273,7 +286,7 @@ func (self *InjectorTests) TestGetLazyNil() {
 				provider: reflect.ValueOf(func() (*int, Annotation1) {
 					return nil, Annotation1{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 			{
@@ -283,7 +296,7 @@ func (self *InjectorTests) TestGetLazyNil() {
 				provider: reflect.ValueOf(func(value func() *int, _ Annotation1) (*int, Annotation2) {
 					return value(), Annotation2{}
 				}),
-				arguments: []providerKey{{
+				arguments: []Key{{
 					valueType:      reflect.TypeOf(func() *int { return nil }),
 					annotationType: reflect.TypeOf(Annotation1{}),
 				}},
@@ -296,7 +309,7 @@ func (self *InjectorTests) TestGetLazyNil() {
 
 func (self *InjectorTests) TestGetLazyError() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -304,7 +317,7 @@ func (self *InjectorTests) TestGetLazyError() {
 				provider: reflect.ValueOf(func() (int, Annotation1, error) {
 					return 0, Annotation1{}, testError
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  true,
 			},
 			{
@@ -314,7 +327,7 @@ func (self *InjectorTests) TestGetLazyError() {
 				provider: reflect.ValueOf(func(value func() int, _ Annotation1) (int, Annotation2) {
 					return value(), Annotation2{}
 				}),
-				arguments: []providerKey{{
+				arguments: []Key{{
 					valueType:      reflect.TypeOf(func() int { return 0 }),
 					annotationType: reflect.TypeOf(Annotation1{}),
 				}},
@@ -323,12 +336,16 @@ func (self *InjectorTests) TestGetLazyError() {
 		},
 	})
 	_, err := self.injector.Get(new(int), Annotation2{})
-	self.Equal(testError, err.(provideError).cause.(provideError).cause)
+	self.Equal(ProviderFailedError{
+		Key:   testKey(Annotation1{}),
+		Path:  []Key{testKey(Annotation2{}), testKey(Annotation1{})},
+		Cause: testError,
+	}, err)
 }
 
 func (self *InjectorTests) TestGetLazyPanic() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -336,7 +353,7 @@ func (self *InjectorTests) TestGetLazyPanic() {
 				provider: reflect.ValueOf(func() (int, Annotation1) {
 					panic(testError)
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 			{
@@ -346,7 +363,7 @@ func (self *InjectorTests) TestGetLazyPanic() {
 				provider: reflect.ValueOf(func(value func() int, _ Annotation1) (int, Annotation2) {
 					return value(), Annotation2{}
 				}),
-				arguments: []providerKey{{
+				arguments: []Key{{
 					valueType:      reflect.TypeOf(func() int { return 0 }),
 					annotationType: reflect.TypeOf(Annotation1{}),
 				}},
@@ -362,7 +379,7 @@ func (self *InjectorTests) TestGetLazyPanic() {
 func (self *InjectorTests) TestGetLazyDoesNotCallProviderUntilRequested() {
 	calledLazyProvider := false
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -371,7 +388,7 @@ func (self *InjectorTests) TestGetLazyDoesNotCallProviderUntilRequested() {
 					calledLazyProvider = true
 					return testValue, Annotation1{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 			{
@@ -381,7 +398,7 @@ func (self *InjectorTests) TestGetLazyDoesNotCallProviderUntilRequested() {
 				provider: reflect.ValueOf(func(value func() int, _ Annotation1) (int, Annotation2) {
 					return 1, Annotation2{}
 				}),
-				arguments: []providerKey{{
+				arguments: []Key{{
 					valueType:      reflect.TypeOf(func() int { return 0 }),
 					annotationType: reflect.TypeOf(Annotation1{}),
 				}},
@@ -396,7 +413,7 @@ func (self *InjectorTests) TestGetLazyDoesNotCallProviderUntilRequested() {
 func (self *InjectorTests) TestGetLazyCached() {
 	counter := testValue
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -407,7 +424,7 @@ func (self *InjectorTests) TestGetLazyCached() {
 					}()
 					return counter, Annotation1{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 				cached:    true,
 			},
@@ -418,7 +435,7 @@ func (self *InjectorTests) TestGetLazyCached() {
 				provider: reflect.ValueOf(func(value func() int, _ Annotation1) (int, Annotation2) {
 					return value(), Annotation2{}
 				}),
-				arguments: []providerKey{{
+				arguments: []Key{{
 					valueType:      reflect.TypeOf(func() int { return 0 }),
 					annotationType: reflect.TypeOf(Annotation1{}),
 				}},
@@ -433,7 +450,7 @@ func (self *InjectorTests) TestGetLazyCached() {
 func (self *InjectorTests) TestCallStoredLazyProvider() {
 	var lazyProvider func() int = nil
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -441,7 +458,7 @@ func (self *InjectorTests) TestCallStoredLazyProvider() {
 				provider: reflect.ValueOf(func() (int, Annotation1) {
 					return testValue, Annotation1{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 			{
@@ -452,7 +469,7 @@ func (self *InjectorTests) TestCallStoredLazyProvider() {
 					lazyProvider = value
 					return testValue + 1, Annotation2{}
 				}),
-				arguments: []providerKey{{
+				arguments: []Key{{
 					valueType:      reflect.TypeOf(func() int { return 0 }),
 					annotationType: reflect.TypeOf(Annotation1{}),
 				}},
@@ -466,10 +483,161 @@ func (self *InjectorTests) TestCallStoredLazyProvider() {
 	})
 }
 
+func (self *InjectorTests) TestGetLazyReturnsError() {
+	self.initInjector(&providersData{
+		providers: map[Key]providerData{
+			{
+				valueType:      reflect.TypeOf(int(0)),
+				annotationType: reflect.TypeOf(Annotation1{}),
+			}: {
+				provider: reflect.ValueOf(func() (int, Annotation1, error) {
+					return 0, Annotation1{}, testError
+				}),
+				arguments: []Key{},
+				hasError:  true,
+			},
+			{
+				valueType:      reflect.TypeOf(int(0)),
+				annotationType: reflect.TypeOf(Annotation2{}),
+			}: {
+				provider: reflect.ValueOf(func(value func() (int, error), _ Annotation1) (int, Annotation2) {
+					result, err := value()
+					if err != nil {
+						self.Equal(ProviderFailedError{
+							Key:   testKey(Annotation1{}),
+							Path:  []Key{testKey(Annotation2{}), testKey(Annotation1{})},
+							Cause: testError,
+						}, err)
+						return testValue, Annotation2{}
+					}
+					return result, Annotation2{}
+				}),
+				arguments: []Key{{
+					valueType:      reflect.TypeOf(func() (int, error) { return 0, nil }),
+					annotationType: reflect.TypeOf(Annotation1{}),
+				}},
+				hasError: false,
+			},
+		},
+	})
+	self.Equal(testValue, self.getInt(Annotation2{}))
+}
+
+func (self *InjectorTests) TestGetLazyReturnsErrorValue() {
+	self.initInjector(&providersData{
+		providers: map[Key]providerData{
+			{
+				valueType:      reflect.TypeOf(int(0)),
+				annotationType: reflect.TypeOf(Annotation1{}),
+			}: {
+				provider: reflect.ValueOf(func() (int, Annotation1) {
+					return testValue, Annotation1{}
+				}),
+				arguments: []Key{},
+				hasError:  false,
+			},
+			{
+				valueType:      reflect.TypeOf(int(0)),
+				annotationType: reflect.TypeOf(Annotation2{}),
+			}: {
+				provider: reflect.ValueOf(func(value func() (int, error), _ Annotation1) (int, Annotation2, error) {
+					result, err := value()
+					return result + 1, Annotation2{}, err
+				}),
+				arguments: []Key{{
+					valueType:      reflect.TypeOf(func() (int, error) { return 0, nil }),
+					annotationType: reflect.TypeOf(Annotation1{}),
+				}},
+				hasError: true,
+			},
+		},
+	})
+	self.Equal(testValue+1, self.getInt(Annotation2{}))
+}
+
+func (self *InjectorTests) TestCallStoredLazyProviderReturnsError() {
+	var lazyProvider func() (int, error) = nil
+	self.initInjector(&providersData{
+		providers: map[Key]providerData{
+			{
+				valueType:      reflect.TypeOf(int(0)),
+				annotationType: reflect.TypeOf(Annotation1{}),
+			}: {
+				provider: reflect.ValueOf(func() (int, Annotation1) {
+					return testValue, Annotation1{}
+				}),
+				arguments: []Key{},
+				hasError:  false,
+			},
+			{
+				valueType:      reflect.TypeOf(int(0)),
+				annotationType: reflect.TypeOf(Annotation2{}),
+			}: {
+				provider: reflect.ValueOf(func(value func() (int, error), _ Annotation1) (int, Annotation2) {
+					lazyProvider = value
+					return testValue + 1, Annotation2{}
+				}),
+				arguments: []Key{{
+					valueType:      reflect.TypeOf(func() (int, error) { return 0, nil }),
+					annotationType: reflect.TypeOf(Annotation1{}),
+				}},
+				hasError: false,
+			},
+		},
+	})
+	self.getInt(Annotation2{})
+	value, err := lazyProvider()
+	self.Equal(0, value)
+	self.Equal(injectOutsideInjectorCallError, err)
+}
+
+func (self *InjectorTests) TestGetLazyCycle() {
+	self.initInjector(&providersData{
+		providers: map[Key]providerData{
+			{
+				valueType:      reflect.TypeOf(int(0)),
+				annotationType: reflect.TypeOf(Annotation1{}),
+			}: {
+				provider: reflect.ValueOf(func(value func() int, _ Annotation2) (int, Annotation1) {
+					return value(), Annotation1{}
+				}),
+				arguments: []Key{{
+					valueType:      reflect.TypeOf(func() int { return 0 }),
+					annotationType: reflect.TypeOf(Annotation2{}),
+				}},
+				hasError: false,
+			},
+			{
+				valueType:      reflect.TypeOf(int(0)),
+				annotationType: reflect.TypeOf(Annotation2{}),
+			}: {
+				provider: reflect.ValueOf(func(value int, _ Annotation1) (int, Annotation2) {
+					return value, Annotation2{}
+				}),
+				arguments: []Key{{
+					valueType:      reflect.TypeOf(int(0)),
+					annotationType: reflect.TypeOf(Annotation1{}),
+				}},
+				hasError: false,
+			},
+		},
+	})
+	_, err := self.injector.Get(new(int), Annotation1{})
+	self.Require().NotNil(err)
+	self.Equal(CycleError{
+		Steps: []CycleStep{
+			{Key: testKey(Annotation1{})},
+			{Key: testKey(Annotation2{}), Lazy: true},
+			{Key: testKey(Annotation1{})},
+		},
+		Path: []Key{testKey(Annotation1{}), testKey(Annotation2{}), testKey(Annotation1{})},
+	}, err)
+}
+
 func (self *InjectorTests) TestProvideFunctionAlias() {
 	type FuncAlias func() int
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(FuncAlias(nil)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -477,7 +645,7 @@ func (self *InjectorTests) TestProvideFunctionAlias() {
 				provider: reflect.ValueOf(func() (FuncAlias, Annotation1) {
 					return func() int { return testValue }, Annotation1{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 			{
@@ -487,7 +655,7 @@ func (self *InjectorTests) TestProvideFunctionAlias() {
 				provider: reflect.ValueOf(func(value FuncAlias, _ Annotation1) (FuncAlias, Annotation2) {
 					return func() int { return value() + 1 }, Annotation2{}
 				}),
-				arguments: []providerKey{{
+				arguments: []Key{{
 					valueType:      reflect.TypeOf(FuncAlias(nil)),
 					annotationType: reflect.TypeOf(Annotation1{}),
 				}},
@@ -505,7 +673,7 @@ func (self *InjectorTests) TestProvideFunctionAlias() {
 
 func (self *InjectorTests) TestGetTransitive() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -513,7 +681,7 @@ func (self *InjectorTests) TestGetTransitive() {
 				provider: reflect.ValueOf(func() (int, Annotation1) {
 					return testValue, Annotation1{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 			{
@@ -523,7 +691,7 @@ func (self *InjectorTests) TestGetTransitive() {
 				provider: reflect.ValueOf(func(value int, _ Annotation1) (int, Annotation2) {
 					return value * 2, Annotation2{}
 				}),
-				arguments: []providerKey{{
+				arguments: []Key{{
 					valueType:      reflect.TypeOf(int(0)),
 					annotationType: reflect.TypeOf(Annotation1{}),
 				}},
@@ -537,7 +705,7 @@ func (self *InjectorTests) TestGetTransitive() {
 
 func (self *InjectorTests) TestGetTransitiveMultiple() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -545,7 +713,7 @@ func (self *InjectorTests) TestGetTransitiveMultiple() {
 				provider: reflect.ValueOf(func() (int, Annotation1) {
 					return testValue, Annotation1{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 			{
@@ -555,7 +723,7 @@ func (self *InjectorTests) TestGetTransitiveMultiple() {
 				provider: reflect.ValueOf(func() (int, Annotation2) {
 					return testValue * 2, Annotation2{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 			{
@@ -566,7 +734,7 @@ func (self *InjectorTests) TestGetTransitiveMultiple() {
 					value2 int, _ Annotation2) (int, Annotation3) {
 					return value1 + value2, Annotation3{}
 				}),
-				arguments: []providerKey{{
+				arguments: []Key{{
 					valueType:      reflect.TypeOf(int(0)),
 					annotationType: reflect.TypeOf(Annotation1{}),
 				}, {
@@ -583,7 +751,7 @@ func (self *InjectorTests) TestGetTransitiveMultiple() {
 
 func (self *InjectorTests) TestMustGet() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{
+		providers: map[Key]providerData{
 			{
 				valueType:      reflect.TypeOf(int(0)),
 				annotationType: reflect.TypeOf(Annotation1{}),
@@ -591,7 +759,7 @@ func (self *InjectorTests) TestMustGet() {
 				provider: reflect.ValueOf(func() (int, Annotation1) {
 					return testValue, Annotation1{}
 				}),
-				arguments: []providerKey{},
+				arguments: []Key{},
 				hasError:  false,
 			},
 		},
@@ -602,11 +770,11 @@ func (self *InjectorTests) TestMustGet() {
 
 func (self *InjectorTests) TestMustGetPanic() {
 	self.initInjector(&providersData{
-		providers: map[providerKey]providerData{},
+		providers: map[Key]providerData{},
 	})
 	defer func() {
 		err := recover()
-		self.NotNil(err.(provideError).cause)
+		self.IsType(MissingProviderError{}, err)
 	}()
 	_ = self.injector.MustGet((*int)(nil), Annotation1{}).(int)
 }
@@ -628,10 +796,7 @@ func (self *InjectorTests) getIntPtr(annotation Annotation) *int {
 }
 
 func (self *InjectorTests) initInjector(providers *providersData) {
-	self.injector = &Injector{
-		providers: providers,
-		cache:     map[providerKey]valueErrorPair{},
-	}
+	self.injector = newInjector(providers)
 }
 
 func TestInjector(t *testing.T) {
@@ -666,3 +831,193 @@ func (self *InjectorOfTests) TestInvalidModule() {
 func TestInjectorOf(t *testing.T) {
 	suite.Run(t, new(InjectorOfTests))
 }
+
+type GetContextTests struct {
+	suite.Suite
+}
+
+type contextTestKey struct{}
+
+type contextTestModule struct {
+	calls *int32
+}
+
+func (self contextTestModule) ProvideValue(ctx context.Context) (string, Annotation1) {
+	value, _ := ctx.Value(contextTestKey{}).(string)
+	return value, Annotation1{}
+}
+
+func (self contextTestModule) ProvideCachedValue(
+	ctx context.Context,
+	value string, _ Annotation1,
+) (string, Annotation2) {
+	atomic.AddInt32(self.calls, 1)
+	return value + "!", Annotation2{}
+}
+
+func (self contextTestModule) ProvideFromInjector(injector *Injector, _ Builtin) (string, Annotation3, error) {
+	value, err := injector.Get(new(string), Annotation1{})
+	if err != nil {
+		return "", Annotation3{}, err
+	}
+	return value.(string), Annotation3{}, nil
+}
+
+func (self *GetContextTests) TestGetContext() {
+	injector, err := InjectorOf(contextTestModule{calls: new(int32)})
+	self.Require().Nil(err)
+	ctx := context.WithValue(context.Background(), contextTestKey{}, "value")
+
+	value, err := injector.GetContext(ctx, new(string), Annotation1{})
+	self.Require().Nil(err)
+	self.Equal("value", value)
+
+	value, err = injector.GetContext(ctx, new(string), Annotation2{})
+	self.Require().Nil(err)
+	self.Equal("value!", value)
+}
+
+func (self *GetContextTests) TestGetWithoutContext() {
+	injector, err := InjectorOf(contextTestModule{calls: new(int32)})
+	self.Require().Nil(err)
+	self.Equal("", injector.MustGet(new(string), Annotation1{}))
+}
+
+func (self *GetContextTests) TestGetFromProvider() {
+	injector, err := InjectorOf(contextTestModule{calls: new(int32)})
+	self.Require().Nil(err)
+	ctx := context.WithValue(context.Background(), contextTestKey{}, "value")
+
+	value, err := injector.GetContext(ctx, new(string), Annotation3{})
+	self.Require().Nil(err)
+	self.Equal("value", value)
+}
+
+func (self *GetContextTests) TestCanceled() {
+	module := contextTestModule{calls: new(int32)}
+	injector, err := InjectorOf(module)
+	self.Require().Nil(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = injector.GetContext(ctx, new(string), Annotation2{})
+	self.Require().NotNil(err)
+	self.Equal(CanceledError{
+		Key:   KeyOf(new(string), Annotation2{}),
+		Path:  []Key{KeyOf(new(string), Annotation2{})},
+		Cause: context.Canceled,
+	}, err)
+	self.True(errors.Is(err, context.Canceled))
+	self.Equal(int32(0), *module.calls)
+
+	// Canceled values are not cached.
+	value, err := injector.GetContext(context.Background(), new(string), Annotation2{})
+	self.Require().Nil(err)
+	self.Equal("!", value)
+	self.Equal(int32(1), *module.calls)
+}
+
+func TestGetContext(t *testing.T) {
+	suite.Run(t, new(GetContextTests))
+}
+
+type RecoverPanicsTests struct {
+	suite.Suite
+}
+
+type recoverPanicsTestModule struct {
+	calls *int
+}
+
+func (self recoverPanicsTestModule) ProvideCachedPanicking() (int, Annotation1) {
+	*self.calls += 1
+	var value *int
+	return *value, Annotation1{}
+}
+
+func (self recoverPanicsTestModule) ProvideDependent(value int, _ Annotation1) (int, Annotation2) {
+	return value, Annotation2{}
+}
+
+func (self recoverPanicsTestModule) ProvideLazyDependent(value func() int, _ Annotation1) (int, Annotation3) {
+	return value(), Annotation3{}
+}
+
+type recoverPanicsOnceTestModule struct {
+	panicked *bool
+}
+
+func (self recoverPanicsOnceTestModule) ProvideCachedPanickingOnce() (int, Annotation1) {
+	if !*self.panicked {
+		*self.panicked = true
+		panic(testError)
+	}
+	return testValue, Annotation1{}
+}
+
+func (self recoverPanicsOnceTestModule) ProvideCachedDependent(value int, _ Annotation1) (int, Annotation2) {
+	return value, Annotation2{}
+}
+
+func (self *RecoverPanicsTests) TestRecoverPanics() {
+	module := recoverPanicsTestModule{calls: new(int)}
+	injector, err := InjectorWithOptions(Options{RecoverPanics: true}, module)
+	self.Require().Nil(err)
+
+	_, err = injector.Get(new(int), Annotation2{})
+	self.Require().NotNil(err)
+	panicErr, ok := err.(ProviderPanicError)
+	self.Require().True(ok)
+	self.Equal(testKey(Annotation1{}), panicErr.Key)
+	self.Equal([]Key{testKey(Annotation2{}), testKey(Annotation1{})}, panicErr.Path)
+	self.Implements((*runtime.Error)(nil), panicErr.Value)
+	self.Contains(string(panicErr.Stack), "ProvideCachedPanicking")
+	self.Contains(err.Error(), "int/inject.Annotation1 panicked")
+
+	// Panics are not cached.
+	_, err = injector.Get(new(int), Annotation1{})
+	self.IsType(ProviderPanicError{}, err)
+	self.Equal(2, *module.calls)
+}
+
+func (self *RecoverPanicsTests) TestDependencyPanicIsNotCached() {
+	injector, err := InjectorWithOptions(Options{RecoverPanics: true}, recoverPanicsOnceTestModule{panicked: new(bool)})
+	self.Require().Nil(err)
+
+	_, err = injector.Get(new(int), Annotation2{})
+	self.Require().IsType(ProviderPanicError{}, err)
+	self.Equal(testKey(Annotation1{}), err.(ProviderPanicError).Key)
+
+	value, err := injector.Get(new(int), Annotation1{})
+	self.Require().Nil(err)
+	self.Equal(testValue, value)
+
+	// The dependent value is provided again with the recovered dependency.
+	value, err = injector.Get(new(int), Annotation2{})
+	self.Require().Nil(err)
+	self.Equal(testValue, value)
+}
+
+func (self *RecoverPanicsTests) TestRecoverPanicsInLazyDependency() {
+	injector, err := InjectorWithOptions(Options{RecoverPanics: true}, recoverPanicsTestModule{calls: new(int)})
+	self.Require().Nil(err)
+
+	_, err = injector.Get(new(int), Annotation3{})
+	self.Require().NotNil(err)
+	panicErr, ok := err.(ProviderPanicError)
+	self.Require().True(ok)
+	self.Equal(testKey(Annotation1{}), panicErr.Key)
+	self.Equal([]Key{testKey(Annotation3{}), testKey(Annotation1{})}, panicErr.Path)
+}
+
+func (self *RecoverPanicsTests) TestPanicsByDefault() {
+	injector, err := InjectorOf(recoverPanicsTestModule{calls: new(int)})
+	self.Require().Nil(err)
+	self.Panics(func() {
+		_, _ = injector.Get(new(int), Annotation2{})
+	})
+}
+
+func TestRecoverPanics(t *testing.T) {
+	suite.Run(t, new(RecoverPanicsTests))
+}
@@ -0,0 +1,250 @@
+// inspect implements the commands of the go-inject tool that inspect and validate a module graph.
+// The tool generates a program that calls `Main` with the inspected module, but `Run` can also be
+// called directly, for example from a test.
+// See `Usage` for the commands.
+package inspect
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/monnoroch/go-inject"
+	"github.com/monnoroch/go-inject/graph"
+)
+
+// Exit codes of `Main`.
+const (
+	// The command succeeded.
+	ExitOk = 0
+	// The command failed, for example the module graph is not valid.
+	ExitFailed = 1
+	// The command was used incorrectly.
+	ExitUsage = 2
+)
+
+// The usage of the commands.
+const Usage = `Commands:
+	check                          validate provider signatures, missing dependencies and cycles
+	graph [-format dot|mermaid|json]
+	                               print the dependency graph
+	keys                           list provided keys with their modules and source locations
+	why <type> <annotation>        show the paths that require the key`
+
+// An error caused by incorrect usage of the commands.
+type UsageError struct {
+	Message string
+}
+
+func (self UsageError) Error() string {
+	return fmt.Sprintf("%s\n%s", self.Message, Usage)
+}
+
+// Run the command with its arguments in the arguments list on the module, writing the output
+// to stdout.
+func Run(module inject.Module, arguments []string, stdout io.Writer) error {
+	if len(arguments) == 0 {
+		return UsageError{Message: "No command"}
+	}
+	command, arguments := arguments[0], arguments[1:]
+	switch command {
+	case "check":
+		return check(module, arguments, stdout)
+	case "graph":
+		return printGraph(module, arguments, stdout)
+	case "keys":
+		return keys(module, arguments, stdout)
+	case "why":
+		return why(module, arguments, stdout)
+	default:
+		return UsageError{Message: fmt.Sprintf("Unknown command %q", command)}
+	}
+}
+
+// Run the command from the process arguments on the module and exit the process with
+// one of the exit codes.
+// Errors are printed to the standard error.
+func Main(module inject.Module) {
+	err := Run(module, os.Args[1:], os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(ExitCode(err))
+}
+
+// Get an exit code for an error returned by `Run`.
+func ExitCode(err error) int {
+	switch err.(type) {
+	case nil:
+		return ExitOk
+	case UsageError:
+		return ExitUsage
+	default:
+		return ExitFailed
+	}
+}
+
+func check(module inject.Module, arguments []string, stdout io.Writer) error {
+	if len(arguments) != 0 {
+		return UsageError{Message: "The check command has no arguments"}
+	}
+	if err := inject.ValidateGraph(module); err != nil {
+		return err
+	}
+	injector, err := inject.InjectorOf(module)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "The module graph is valid: %d keys provided\n", len(injector.Keys()))
+	return nil
+}
+
+func printGraph(module inject.Module, arguments []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("graph", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	format := flags.String("format", "dot", "")
+	if err := flags.Parse(arguments); err != nil {
+		return UsageError{Message: err.Error()}
+	}
+	if flags.NArg() != 0 {
+		return UsageError{Message: "The graph command has no positional arguments"}
+	}
+
+	dependencyGraph, err := graph.OfModules(module)
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "dot":
+		_, err = io.WriteString(stdout, dependencyGraph.DOT())
+	case "mermaid":
+		_, err = io.WriteString(stdout, dependencyGraph.Mermaid())
+	case "json":
+		var data []byte
+		data, err = dependencyGraph.JSON()
+		if err == nil {
+			_, err = fmt.Fprintf(stdout, "%s\n", data)
+		}
+	default:
+		return UsageError{Message: fmt.Sprintf("Unknown graph format %q", *format)}
+	}
+	return err
+}
+
+func keys(module inject.Module, arguments []string, stdout io.Writer) error {
+	if len(arguments) != 0 {
+		return UsageError{Message: "The keys command has no arguments"}
+	}
+	injector, err := inject.InjectorOf(module)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "KEY\tMODULE\tCACHED\tSCOPED\tLOCATION")
+	for _, key := range injector.Keys() {
+		location := "unknown"
+		if file, line := injector.Location(key); file != "" {
+			location = fmt.Sprintf("%s:%d", file, line)
+		}
+		fmt.Fprintf(
+			writer, "%v\t%T\t%t\t%t\t%s\n",
+			key, injector.Module(key), injector.IsCached(key), injector.IsScoped(key), location,
+		)
+	}
+	return writer.Flush()
+}
+
+func why(module inject.Module, arguments []string, stdout io.Writer) error {
+	if len(arguments) != 2 {
+		return UsageError{Message: "The why command needs a type and an annotation"}
+	}
+	injector, err := inject.InjectorOf(module)
+	if err != nil {
+		return err
+	}
+	key, err := findKey(injector, arguments[0], arguments[1])
+	if err != nil {
+		return err
+	}
+
+	paths := requiringPaths(injector, key)
+	if len(paths) == 0 {
+		fmt.Fprintf(stdout, "%v is not required by any provided value\n", key)
+		return nil
+	}
+	fmt.Fprintf(stdout, "%v is required by:\n", key)
+	for _, path := range paths {
+		fmt.Fprintf(stdout, "\t%s\n", path)
+	}
+	return nil
+}
+
+// Find a key of a provided value or of a dependency by the strings of its type and annotation,
+// as in `Key.String()`.
+func findKey(injector *inject.Injector, valueType string, annotation string) (inject.Key, error) {
+	matches := func(key inject.Key) bool {
+		return key.ValueType().String() == valueType && key.AnnotationType().String() == annotation
+	}
+	for _, key := range injector.Keys() {
+		if matches(key) {
+			return key, nil
+		}
+		for _, dependency := range injector.Dependencies(key) {
+			if matches(dependency.Key) {
+				return dependency.Key, nil
+			}
+		}
+	}
+	return inject.Key{}, fmt.Errorf("Key %s/%s is neither provided nor a dependency", valueType, annotation)
+}
+
+// Get the shortest paths to the key from every value that requires it and is not a dependency
+// of any other value, sorted.
+// Lazy dependencies are shown as "~>", like in `inject.CycleError`.
+func requiringPaths(injector *inject.Injector, key inject.Key) []string {
+	// The next step towards the key from every value that requires it.
+	next := map[inject.Key]inject.Dependency{}
+	visited := map[inject.Key]bool{key: true}
+	roots := []inject.Key{}
+	queue := []inject.Key{key}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		dependents := injector.Dependents(current)
+		if len(dependents) == 0 && current != key {
+			roots = append(roots, current)
+		}
+		for _, dependent := range dependents {
+			if visited[dependent.Key] {
+				continue
+			}
+			visited[dependent.Key] = true
+			next[dependent.Key] = inject.Dependency{Key: current, Lazy: dependent.Lazy}
+			queue = append(queue, dependent.Key)
+		}
+	}
+
+	paths := make([]string, len(roots))
+	for index, root := range roots {
+		var path strings.Builder
+		path.WriteString(root.String())
+		for current := root; current != key; {
+			step := next[current]
+			if step.Lazy {
+				path.WriteString(" ~> ")
+			} else {
+				path.WriteString(" -> ")
+			}
+			path.WriteString(step.Key.String())
+			current = step.Key
+		}
+		paths[index] = path.String()
+	}
+	sort.Strings(paths)
+	return paths
+}
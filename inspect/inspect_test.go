@@ -0,0 +1,141 @@
+package inspect
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/monnoroch/go-inject"
+)
+
+type annotation1 struct{}
+type annotation2 struct{}
+type annotation3 struct{}
+type annotation4 struct{}
+
+type testModule struct{}
+
+func (self testModule) ProvideCachedBase() (int, annotation1) {
+	return 1, annotation1{}
+}
+
+func (self testModule) ProvideScopedDependent(value int, _ annotation1) (int, annotation2) {
+	return value + 1, annotation2{}
+}
+
+func (self testModule) ProvideLazyDependent(value func() int, _ annotation1) (int, annotation3) {
+	return value() + 2, annotation3{}
+}
+
+type testMissingModule struct{}
+
+func (self testMissingModule) ProvideValue(value int, _ annotation4) (int, annotation1) {
+	return value, annotation1{}
+}
+
+type InspectTests struct {
+	suite.Suite
+}
+
+func (self *InspectTests) run(module inject.Module, arguments ...string) (string, error) {
+	var stdout bytes.Buffer
+	err := Run(module, arguments, &stdout)
+	return stdout.String(), err
+}
+
+func (self *InspectTests) TestNoCommand() {
+	_, err := self.run(testModule{})
+	self.IsType(UsageError{}, err)
+	self.Equal(ExitUsage, ExitCode(err))
+}
+
+func (self *InspectTests) TestUnknownCommand() {
+	_, err := self.run(testModule{}, "unknown")
+	self.Equal(UsageError{Message: `Unknown command "unknown"`}, err)
+}
+
+func (self *InspectTests) TestCheck() {
+	output, err := self.run(testModule{}, "check")
+	self.Nil(err)
+	self.Equal("The module graph is valid: 3 keys provided\n", output)
+}
+
+func (self *InspectTests) TestCheckMissingDependency() {
+	_, err := self.run(testMissingModule{}, "check")
+	self.Require().NotNil(err)
+	self.Contains(err.Error(), "int/inspect.annotation4")
+	self.Equal(ExitFailed, ExitCode(err))
+}
+
+func (self *InspectTests) TestGraph() {
+	for format, prefix := range map[string]string{
+		"dot":     "digraph",
+		"mermaid": "flowchart LR",
+		"json":    "{",
+	} {
+		output, err := self.run(testModule{}, "graph", "-format", format)
+		self.Nil(err)
+		self.True(strings.HasPrefix(output, prefix), format)
+	}
+
+	output, err := self.run(testModule{}, "graph")
+	self.Nil(err)
+	self.True(strings.HasPrefix(output, "digraph"))
+}
+
+func (self *InspectTests) TestGraphUnknownFormat() {
+	_, err := self.run(testModule{}, "graph", "-format", "svg")
+	self.Equal(UsageError{Message: `Unknown graph format "svg"`}, err)
+}
+
+func (self *InspectTests) TestKeys() {
+	output, err := self.run(testModule{}, "keys")
+	self.Nil(err)
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	self.Require().Equal(4, len(lines))
+	self.Equal([]string{"KEY", "MODULE", "CACHED", "SCOPED", "LOCATION"}, strings.Fields(lines[0]))
+	fields := strings.Fields(lines[1])
+	self.Require().Equal(5, len(fields))
+	self.Equal([]string{"int/inspect.annotation1", "inspect.testModule", "true", "false"}, fields[:4])
+	self.Contains(fields[4], "inspect_test.go:")
+	self.Equal([]string{"int/inspect.annotation2", "inspect.testModule", "false", "true"},
+		strings.Fields(lines[2])[:4])
+}
+
+func (self *InspectTests) TestWhy() {
+	output, err := self.run(testModule{}, "why", "int", "inspect.annotation1")
+	self.Nil(err)
+	self.Equal("int/inspect.annotation1 is required by:\n"+
+		"\tint/inspect.annotation2 -> int/inspect.annotation1\n"+
+		"\tint/inspect.annotation3 ~> int/inspect.annotation1\n", output)
+}
+
+func (self *InspectTests) TestWhyMissingDependency() {
+	output, err := self.run(testMissingModule{}, "why", "int", "inspect.annotation4")
+	self.Nil(err)
+	self.Equal("int/inspect.annotation4 is required by:\n"+
+		"\tint/inspect.annotation1 -> int/inspect.annotation4\n", output)
+}
+
+func (self *InspectTests) TestWhyNotRequired() {
+	output, err := self.run(testModule{}, "why", "int", "inspect.annotation2")
+	self.Nil(err)
+	self.Equal("int/inspect.annotation2 is not required by any provided value\n", output)
+}
+
+func (self *InspectTests) TestWhyUnknownKey() {
+	_, err := self.run(testModule{}, "why", "string", "inspect.annotation1")
+	self.Require().NotNil(err)
+	self.Equal("Key string/inspect.annotation1 is neither provided nor a dependency", err.Error())
+}
+
+func (self *InspectTests) TestWhyArguments() {
+	_, err := self.run(testModule{}, "why", "int")
+	self.IsType(UsageError{}, err)
+}
+
+func TestInspect(t *testing.T) {
+	suite.Run(t, new(InspectTests))
+}
@@ -1,79 +1,152 @@
-/// A dependency injection library for Go.
-///
-/// This library provides the `Injector` type that is used for providing values and
-/// after being configured with a collection of `Module`-s.
-/// The library also provides iinterfaces for defining modules both from structs with provider methods and
-/// dynamically generated providers.
+// A dependency injection library for Go.
+//
+// This library provides the `Injector` type that is used for providing values and
+// after being configured with a collection of `Module`-s.
+// The library also provides iinterfaces for defining modules both from structs with provider methods and
+// dynamically generated providers.
 package inject
 
 import (
 	"reflect"
 )
 
-/// Module is the interface that has to be implemented by all modules.
-/// It is empty, so implementation is trivial.
-/// In addition to this interface all Modules have to have methods that have two or three outputs:
-/// - A value type.
-/// - An annotation type.
-/// - Optionally, an error.
-/// These methods can have inputs that should come in pairs: values and their annotations.
+// Module is the interface that has to be implemented by all modules.
+// It is empty, so implementation is trivial.
+// In addition to this interface all Modules have to have methods that have two or three outputs:
+// - A value type.
+// - An annotation type.
+// - Optionally, an error.
+// These methods can have inputs that should come in pairs: values and their annotations.
 type Module interface{}
 
-/// Annotation is the interface that has to be implemented by all annotations.
-/// It is empty, so implementation is trivial.
+// Annotation is the interface that has to be implemented by all annotations.
+// It is empty, so implementation is trivial.
 type Annotation interface{}
 
-/// A dynamic provider is a type containing all the data about a provider that
-/// the injector needs to be able to provide it.
+// A dynamic provider is a type containing all the data about a provider that
+// the injector needs to be able to provide it.
 type Provider struct {
-	/// A provider function.
+	// A provider function.
 	function reflect.Value
-	/// Whether or not to cache this provider.
+	// Whether or not to cache this provider.
 	cached bool
+	// Whether to cache values of this provider in each scope of the injector.
+	scoped bool
+	// Whether the provider provides an element of a set instead of the value of its key.
+	intoSet bool
+	// What to do with duplicate elements of the set.
+	duplicates DuplicateElements
+	// The code pointer of a function whose source location is reported as the provider's location,
+	// if it is not the provider function itself.
+	source uintptr
 }
 
-/// Create a new provider from either a function or a `reflect.Value` with a function.
+// Create a new provider from either a function or a `reflect.Value` with a function.
 func NewProvider(function interface{}) Provider {
+	return Provider{
+		function: asReflectValue(function),
+	}
+}
+
+func asReflectValue(function interface{}) reflect.Value {
 	reflectFunction, ok := function.(reflect.Value)
 	if !ok {
 		reflectFunction = reflect.ValueOf(function)
 	}
-	return Provider{
-		function: reflectFunction,
-	}
+	return reflectFunction
 }
 
-/// Return the `reflect.Value` with the function of this provider.
+// Return the `reflect.Value` with the function of this provider.
 func (self Provider) Function() reflect.Value {
 	return self.function
 }
 
-/// Test if the provider is valid: has the right number and types of inputs and outputs.
+// Create a version of this provider with another function, either a function or
+// a `reflect.Value` with a function, and the same options and source location.
+// Useful for wrapping providers.
+func (self Provider) WithFunction(function interface{}) Provider {
+	if self.source == 0 {
+		self.source = functionPointer(self.function)
+	}
+	self.function = asReflectValue(function)
+	return self
+}
+
+// Get the source location of the provider's function: the file and the line where it is defined.
+// Returns an empty file name if the location is not known, e.g. for functions created with
+// `reflect.MakeFunc` or method values.
+func (self Provider) Location() (string, int) {
+	source := self.source
+	if source == 0 {
+		source = functionPointer(self.function)
+	}
+	return functionLocation(source)
+}
+
+// Test if the provider is valid: has the right number and types of inputs and outputs.
 func (self Provider) IsValid() bool {
 	functionType := self.Function().Type()
 	return isProvider(functionType) || isProviderWithError(functionType)
 }
 
-/// Create a cached or non-cached version of this provider.
+// Create a cached or non-cached version of this provider.
 func (self Provider) Cached(cached bool) Provider {
 	self.cached = cached
 	return self
 }
 
-/// Test if this provider is cached or not.
+// Test if this provider is cached or not.
 func (self Provider) IsCached() bool {
 	return self.cached
 }
 
-/// Dynamic providers module. A type that, instead of having provider methods,
-/// as with a static providers module, has a method for generating providers dynamically.
+// Create a scoped or non-scoped version of this provider.
+// Values of scoped providers are cached in each scope created with `Injector.NewScope`,
+// and can not be provided outside of scopes.
+func (self Provider) Scoped(scoped bool) Provider {
+	self.scoped = scoped
+	return self
+}
+
+// Test if this provider is scoped or not.
+func (self Provider) IsScoped() bool {
+	return self.scoped
+}
+
+// Create a version of this provider that provides an element of a set: a slice of the
+// provided values with the provider's annotation, with elements of all providers of the set.
+// See `DuplicateElements` for the order of the elements.
+func (self Provider) IntoSet(intoSet bool) Provider {
+	self.intoSet = intoSet
+	return self
+}
+
+// Test if this provider provides an element of a set.
+func (self Provider) IsIntoSet() bool {
+	return self.intoSet
+}
+
+// Create a version of this provider with the policy for duplicate elements of its set.
+// Providers of the same set can not set different policies.
+func (self Provider) WithDuplicates(duplicates DuplicateElements) Provider {
+	self.duplicates = duplicates
+	return self
+}
+
+// Get the policy for duplicate elements of the provider's set.
+func (self Provider) Duplicates() DuplicateElements {
+	return self.duplicates
+}
+
+// Dynamic providers module. A type that, instead of having provider methods,
+// as with a static providers module, has a method for generating providers dynamically.
 type DynamicModule interface {
 	Module
-	/// Generate a list of providers.
+	// Generate a list of providers.
 	Providers() ([]Provider, error)
 }
 
-/// Get the list of providers from the module.
+// Get the list of providers from the module.
 func Providers(module Module) ([]Provider, error) {
 	dynamicModule, ok := module.(DynamicModule)
 	if !ok {
@@ -3,6 +3,7 @@ package inject
 import (
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -50,6 +51,16 @@ func (self *ProviderTests) TestNotCachedProvider() {
 	self.True(provider.IsValid())
 }
 
+func (self *ProviderTests) TestScopedProvider() {
+	function := func() (int, testAnnotation1) {
+		return 0, testAnnotation1{}
+	}
+	provider := NewProvider(function).Scoped(true)
+	self.True(provider.IsScoped())
+	self.False(provider.Scoped(false).IsScoped())
+	self.False(provider.IsCached())
+}
+
 func (self *ProviderTests) TestNotAFunction() {
 	self.False(NewProvider(0).IsValid())
 }
@@ -95,7 +106,43 @@ func (self *ProvidersTests) TestStaticModule() {
 	module := testStaticModule{}
 	actualProviders, err := Providers(module)
 	self.Require().Nil(err)
-	self.Equal([]Provider{NewProvider(reflect.ValueOf(module).MethodByName("Provide"))}, actualProviders)
+	provider := NewProvider(reflect.ValueOf(module).MethodByName("Provide"))
+	provider.source = staticMethodSource(module, "Provide")
+	self.Equal([]Provider{provider}, actualProviders)
+}
+
+func (self *ProvidersTests) TestLocation() {
+	file, line := NewProvider(testFunction).Location()
+	self.True(strings.HasSuffix(file, "interface_test.go"))
+	self.NotZero(line)
+
+	file, line = NewProvider(testFunction).WithFunction(func() {}).Location()
+	self.True(strings.HasSuffix(file, "interface_test.go"))
+	self.NotZero(line)
+
+	file, _ = NewProvider(reflect.MakeFunc(reflect.TypeOf(testFunction), nil)).Location()
+	self.Equal("", file)
+}
+
+func testFunction() {}
+
+func (self *ProvidersTests) TestStaticModuleLocation() {
+	providers, err := Providers(&testStaticModule{})
+	self.Require().Nil(err)
+	file, line := providers[0].Location()
+	self.True(strings.HasSuffix(file, "providers_test.go"))
+	self.NotZero(line)
+}
+
+func (self *ProvidersTests) TestWithFunction() {
+	provider := NewProvider(testFunction).Cached(true).WithFunction(func() {})
+	self.True(provider.IsCached())
+	self.NotEqual(reflect.ValueOf(testFunction).Pointer(), provider.Function().Pointer())
+}
+
+func staticMethodSource(module Module, name string) uintptr {
+	method, _ := reflect.TypeOf(module).MethodByName(name)
+	return method.Func.Pointer()
 }
 
 func (self *ProvidersTests) TestErrorModule() {
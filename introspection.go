@@ -0,0 +1,125 @@
+package inject
+
+import (
+	"sort"
+)
+
+// A dependency between the provider of a key and another key.
+type Dependency struct {
+	Key Key
+	// Whether the dependency is through a lazy argument.
+	Lazy bool
+}
+
+// Get keys of all values provided by the injector's modules, sorted by their string representations.
+// Sets have keys for each of their elements, see `Key.IsElement`.
+// Built-in values are not included.
+func (self *Injector) Keys() []Key {
+	keys := make([]Key, 0, len(self.providers.providers))
+	for key := range self.providers.providers {
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+	return keys
+}
+
+// Test if the injector can provide values of the key: it has a provider for it or
+// it is a built-in value.
+// Values of the key can still fail to be provided if their dependencies can not.
+func (self *Injector) Has(key Key) bool {
+	_, ok := self.providers.providers[key]
+	return ok || isBuiltin(key)
+}
+
+// Get dependencies of the provider of the key in the order of its arguments.
+// Lazy dependencies have the key of the lazily provided value.
+// Values got from the injector injected into the provider are not known in advance and
+// are not included.
+// Returns nil if the key has no provider.
+func (self *Injector) Dependencies(key Key) []Dependency {
+	provider, ok := self.providers.providers[key]
+	if !ok {
+		return nil
+	}
+	dependencies := make([]Dependency, len(provider.arguments))
+	for index, argumentKey := range provider.arguments {
+		dependencies[index] = dependencyOn(argumentKey)
+	}
+	return dependencies
+}
+
+// Get keys of providers that depend on the key, sorted by their string representations.
+// A dependency is lazy if the provider only depends on the key through lazy arguments.
+func (self *Injector) Dependents(key Key) []Dependency {
+	lazy := map[Key]bool{}
+	for dependentKey, provider := range self.providers.providers {
+		for _, argumentKey := range provider.arguments {
+			dependency := dependencyOn(argumentKey)
+			if dependency.Key != key {
+				continue
+			}
+			if isLazy, ok := lazy[dependentKey]; !ok || isLazy {
+				lazy[dependentKey] = dependency.Lazy
+			}
+		}
+	}
+
+	dependents := make([]Dependency, 0, len(lazy))
+	for dependentKey, isLazy := range lazy {
+		dependents = append(dependents, Dependency{Key: dependentKey, Lazy: isLazy})
+	}
+	sort.Slice(dependents, func(i int, j int) bool {
+		return dependents[i].Key.String() < dependents[j].Key.String()
+	})
+	return dependents
+}
+
+// Test if the provider of the key is cached.
+// Returns false if the key has no provider.
+func (self *Injector) IsCached(key Key) bool {
+	return self.providers.providers[key].cached
+}
+
+// Test if the provider of the key is scoped.
+// Returns false if the key has no provider.
+func (self *Injector) IsScoped(key Key) bool {
+	return self.providers.providers[key].scoped
+}
+
+// Test if the key is of a set or a map that the injector provides from elements provided by modules.
+func (self *Injector) IsSet(key Key) bool {
+	_, ok := self.providers.modules[key].(SetModule)
+	return ok
+}
+
+// Test if the provider of the key can return an error.
+// Returns false if the key has no provider.
+func (self *Injector) ReturnsError(key Key) bool {
+	return self.providers.providers[key].hasError
+}
+
+// Get the module that has the provider of the key.
+// Modules combined with `CombineModules` are flattened, so it is one of the combined modules.
+// For keys of sets and maps it is `SetModule`.
+// Returns nil if the key has no provider.
+func (self *Injector) Module(key Key) Module {
+	return self.providers.modules[key]
+}
+
+// Get the source location of the provider of the key: the file and the line where it is defined.
+// Returns an empty file name if the location is not known or the key has no provider.
+func (self *Injector) Location(key Key) (string, int) {
+	location := self.providers.locations[key]
+	return location.file, location.line
+}
+
+// Get the dependency for an argument of a provider.
+func dependencyOn(argumentKey Key) Dependency {
+	if lazyArgumentType := getLazyArgumentType(argumentKey); lazyArgumentType != nil {
+		return Dependency{
+			Key:  Key{valueType: lazyArgumentType, annotationType: argumentKey.annotationType},
+			Lazy: true,
+		}
+	}
+	return Dependency{Key: argumentKey}
+}
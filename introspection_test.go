@@ -0,0 +1,109 @@
+package inject
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type IntrospectionTests struct {
+	suite.Suite
+	injector *Injector
+}
+
+type introspectionTestModule struct{}
+
+func (self introspectionTestModule) ProvideCachedBase() (int, Annotation1) {
+	return testValue, Annotation1{}
+}
+
+func (self introspectionTestModule) ProvideDependent(
+	value int, _ Annotation1,
+	lazyValue func() int, _ Annotation1,
+	injector *Injector, _ Builtin,
+) (int, Annotation2) {
+	return value, Annotation2{}
+}
+
+func (self introspectionTestModule) ProvideLazyDependent(lazyValue func() int, _ Annotation1) (int, Annotation3) {
+	return lazyValue(), Annotation3{}
+}
+
+func (self *IntrospectionTests) SetupTest() {
+	injector, err := InjectorOf(introspectionTestModule{})
+	self.Require().Nil(err)
+	self.injector = injector
+}
+
+func (self *IntrospectionTests) TestKeys() {
+	self.Equal([]Key{
+		testKey(Annotation1{}),
+		testKey(Annotation2{}),
+		testKey(Annotation3{}),
+	}, self.injector.Keys())
+}
+
+func (self *IntrospectionTests) TestHas() {
+	self.True(self.injector.Has(testKey(Annotation1{})))
+	self.True(self.injector.Has(injectorKey))
+	self.False(self.injector.Has(testKey(Annotation4{})))
+}
+
+func (self *IntrospectionTests) TestDependencies() {
+	self.Equal([]Dependency{
+		{Key: testKey(Annotation1{})},
+		{Key: testKey(Annotation1{}), Lazy: true},
+		{Key: injectorKey},
+	}, self.injector.Dependencies(testKey(Annotation2{})))
+	self.Equal([]Dependency{}, self.injector.Dependencies(testKey(Annotation1{})))
+	self.Nil(self.injector.Dependencies(testKey(Annotation4{})))
+}
+
+func (self *IntrospectionTests) TestDependents() {
+	self.Equal([]Dependency{
+		{Key: testKey(Annotation2{})},
+		{Key: testKey(Annotation3{}), Lazy: true},
+	}, self.injector.Dependents(testKey(Annotation1{})))
+	self.Equal([]Dependency{}, self.injector.Dependents(testKey(Annotation2{})))
+}
+
+func (self *IntrospectionTests) TestIsCached() {
+	self.True(self.injector.IsCached(testKey(Annotation1{})))
+	self.False(self.injector.IsCached(testKey(Annotation2{})))
+	self.False(self.injector.IsCached(testKey(Annotation4{})))
+}
+
+type introspectionTestErrorModule struct{}
+
+func (self introspectionTestErrorModule) ProvideWithError() (int, Annotation4, error) {
+	return testValue, Annotation4{}, nil
+}
+
+func (self *IntrospectionTests) TestReturnsError() {
+	injector, err := InjectorOf(introspectionTestModule{}, introspectionTestErrorModule{})
+	self.Require().Nil(err)
+	self.True(injector.ReturnsError(testKey(Annotation4{})))
+	self.False(injector.ReturnsError(testKey(Annotation1{})))
+}
+
+func (self *IntrospectionTests) TestModule() {
+	injector, err := InjectorOf(introspectionTestModule{}, introspectionTestErrorModule{})
+	self.Require().Nil(err)
+	self.Equal(introspectionTestModule{}, injector.Module(testKey(Annotation1{})))
+	self.Equal(introspectionTestErrorModule{}, injector.Module(testKey(Annotation4{})))
+	self.Nil(injector.Module(injectorKey))
+}
+
+func (self *IntrospectionTests) TestLocation() {
+	file, line := self.injector.Location(testKey(Annotation1{}))
+	self.True(strings.HasSuffix(file, "introspection_test.go"))
+	self.NotZero(line)
+
+	file, _ = self.injector.Location(injectorKey)
+	self.Equal("", file)
+}
+
+func TestIntrospection(t *testing.T) {
+	suite.Run(t, new(IntrospectionTests))
+}
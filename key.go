@@ -0,0 +1,45 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Key identifies a value in the injector: the type of the value and the type of its annotation.
+type Key struct {
+	valueType      reflect.Type
+	annotationType reflect.Type
+	// For elements of multibindings, the number of the element starting from 1.
+	element int
+}
+
+// Create a key for values of the type that the pointer points to with the annotation.
+func KeyOf(pointerToType interface{}, annotation Annotation) Key {
+	return Key{
+		valueType:      reflect.TypeOf(pointerToType).Elem(),
+		annotationType: reflect.TypeOf(annotation),
+	}
+}
+
+// Get the type of the values of the key.
+func (self Key) ValueType() reflect.Type {
+	return self.valueType
+}
+
+// Get the type of the annotation of the key.
+func (self Key) AnnotationType() reflect.Type {
+	return self.annotationType
+}
+
+// Test if the key is of an element of a multibinding, such as a set.
+// Such keys have the value type and the annotation type of the element.
+func (self Key) IsElement() bool {
+	return self.element != 0
+}
+
+func (self Key) String() string {
+	if self.IsElement() {
+		return fmt.Sprintf("%v/%v#%d", self.valueType, self.annotationType, self.element-1)
+	}
+	return fmt.Sprintf("%v/%v", self.valueType, self.annotationType)
+}
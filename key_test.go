@@ -0,0 +1,27 @@
+package inject
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type KeyTests struct {
+	suite.Suite
+}
+
+func (self *KeyTests) TestKeyOf() {
+	key := KeyOf(new(int), Annotation1{})
+	self.Equal(testKey(Annotation1{}), key)
+	self.Equal(reflect.TypeOf(0), key.ValueType())
+	self.Equal(reflect.TypeOf(Annotation1{}), key.AnnotationType())
+}
+
+func (self *KeyTests) TestString() {
+	self.Equal("*int/inject.Annotation2", KeyOf(new(*int), Annotation2{}).String())
+}
+
+func TestKey(t *testing.T) {
+	suite.Run(t, new(KeyTests))
+}
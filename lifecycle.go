@@ -0,0 +1,171 @@
+package inject
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Lifecycle is a built-in value for registering actions to run when the injector starts and stops.
+// Providers can depend on it with the `Builtin` annotation to append hooks for the values they provide.
+// Providers are called after their dependencies are provided, so hooks appended by them are run
+// in the dependency order: dependencies are started before and stopped after their dependents.
+// Not cached providers append hooks every time they are called.
+type Lifecycle struct {
+	hooks *lifecycleHooks
+	// The key of the value that the lifecycle is injected as a dependency of.
+	key *Key
+}
+
+// Hook contains actions to run when the injector starts and stops.
+type Hook struct {
+	// An action to run when the injector starts. Can be nil.
+	OnStart func(ctx context.Context) error
+	// An action to run when the injector stops, if OnStart was run successfully. Can be nil.
+	OnStop func(ctx context.Context) error
+	// The maximum duration of each of the actions, in addition to the context deadline.
+	// Zero means no limit.
+	Timeout time.Duration
+}
+
+// Append a hook to the lifecycle.
+// Hooks appended after the injector has started are run on the next start.
+func (self *Lifecycle) Append(hook Hook) {
+	self.hooks.lock.Lock()
+	defer self.hooks.lock.Unlock()
+	self.hooks.hooks = append(self.hooks.hooks, lifecycleHook{hook: hook, key: self.key})
+}
+
+// Append a hook with only the start action.
+func (self *Lifecycle) OnStart(onStart func(ctx context.Context) error) {
+	self.Append(Hook{OnStart: onStart})
+}
+
+// Append a hook with only the stop action.
+func (self *Lifecycle) OnStop(onStop func(ctx context.Context) error) {
+	self.Append(Hook{OnStop: onStop})
+}
+
+// Hooks of the injector.
+type lifecycleHooks struct {
+	// Guards hooks.
+	lock  sync.Mutex
+	hooks []lifecycleHook
+	// Guards started and makes starting and stopping exclusive.
+	runLock sync.Mutex
+	// The number of hooks at the start of the hooks list that are started.
+	started int
+}
+
+type lifecycleHook struct {
+	hook Hook
+	key  *Key
+}
+
+// Get a lifecycle that appends hooks for the path's key.
+func (self *lifecycleHooks) boundTo(path *dependencyPath) *Lifecycle {
+	lifecycle := &Lifecycle{hooks: self}
+	if path != nil {
+		lifecycle.key = &path.key
+	}
+	return lifecycle
+}
+
+// Start the injector: run start actions of all hooks that are not started yet in the order
+// they were appended.
+// If an action fails, the hooks that were started by this call are stopped in the reverse order
+// and the error is returned.
+func (self *Injector) Start(ctx context.Context) error {
+	hooks := self.lifecycle
+	hooks.runLock.Lock()
+	defer hooks.runLock.Unlock()
+
+	hooks.lock.Lock()
+	toStart := hooks.hooks[hooks.started:]
+	hooks.lock.Unlock()
+
+	for index, hook := range toStart {
+		if err := runHookAction(ctx, hook.hook.Timeout, hook.hook.OnStart); err != nil {
+			errs := errorList{hookError{key: hook.key, starting: true, cause: err}}
+			errs = append(errs, stopHooks(ctx, toStart[:index])...)
+			return errs.asError()
+		}
+	}
+	hooks.started += len(toStart)
+	return nil
+}
+
+// Stop the injector: run stop actions of all started hooks in the reverse order.
+// All hooks are stopped even if some of the actions fail.
+// Returns errors of all failed actions.
+func (self *Injector) Stop(ctx context.Context) error {
+	hooks := self.lifecycle
+	hooks.runLock.Lock()
+	defer hooks.runLock.Unlock()
+
+	hooks.lock.Lock()
+	toStop := hooks.hooks[:hooks.started]
+	hooks.lock.Unlock()
+
+	hooks.started = 0
+	return stopHooks(ctx, toStop).asError()
+}
+
+func stopHooks(ctx context.Context, hooks []lifecycleHook) errorList {
+	errs := errorList{}
+	for index := len(hooks) - 1; index >= 0; index -= 1 {
+		hook := hooks[index]
+		if err := runHookAction(ctx, hook.hook.Timeout, hook.hook.OnStop); err != nil {
+			errs = append(errs, hookError{key: hook.key, starting: false, cause: err})
+		}
+	}
+	return errs
+}
+
+// Run the action, but return when the context is done or the timeout expires even if
+// the action ignores the context.
+func runHookAction(ctx context.Context, timeout time.Duration, action func(ctx context.Context) error) error {
+	if action == nil {
+		return nil
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- action(ctx)
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type hookError struct {
+	// The key of the value that appended the hook, if any.
+	key      *Key
+	starting bool
+	cause    error
+}
+
+func (self hookError) Error() string {
+	action := "stopping"
+	if self.starting {
+		action = "starting"
+	}
+	if self.key == nil {
+		return fmt.Sprintf("error %s a hook: %s", action, self.cause.Error())
+	}
+	return fmt.Sprintf("error %s a hook of type %v with annotation %v: %s",
+		action, self.key.valueType, self.key.annotationType, self.cause.Error())
+}
+
+func (self hookError) Unwrap() error {
+	return self.cause
+}
@@ -0,0 +1,124 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LifecycleTests struct {
+	suite.Suite
+}
+
+type lifecycleTestModule struct {
+	events   *[]string
+	startErr error
+}
+
+func (self lifecycleTestModule) hook(name string, startErr error) Hook {
+	return Hook{
+		OnStart: func(_ context.Context) error {
+			*self.events = append(*self.events, "start "+name)
+			return startErr
+		},
+		OnStop: func(_ context.Context) error {
+			*self.events = append(*self.events, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func (self lifecycleTestModule) ProvideCachedBase(lifecycle *Lifecycle, _ Builtin) (int, Annotation1) {
+	lifecycle.Append(self.hook("base", nil))
+	return testValue, Annotation1{}
+}
+
+func (self lifecycleTestModule) ProvideCachedDependent(
+	value int, _ Annotation1,
+	lifecycle *Lifecycle, _ Builtin,
+) (int, Annotation2) {
+	lifecycle.Append(self.hook("dependent", self.startErr))
+	return value + 1, Annotation2{}
+}
+
+func (self lifecycleTestModule) ProvideCachedSlow(lifecycle *Lifecycle, _ Builtin) (int, Annotation3) {
+	lifecycle.Append(Hook{
+		OnStart: func(_ context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		},
+		Timeout: time.Millisecond,
+	})
+	return testValue, Annotation3{}
+}
+
+func (self *LifecycleTests) TestStartAndStop() {
+	events := []string{}
+	injector, err := InjectorOf(lifecycleTestModule{events: &events})
+	self.Require().Nil(err)
+	_ = injector.MustGet(new(int), Annotation2{})
+
+	self.Require().Nil(injector.Start(context.Background()))
+	self.Equal([]string{"start base", "start dependent"}, events)
+	self.Require().Nil(injector.Stop(context.Background()))
+	self.Equal([]string{"start base", "start dependent", "stop dependent", "stop base"}, events)
+}
+
+func (self *LifecycleTests) TestStopWithoutStart() {
+	events := []string{}
+	injector, err := InjectorOf(lifecycleTestModule{events: &events})
+	self.Require().Nil(err)
+	_ = injector.MustGet(new(int), Annotation2{})
+
+	self.Require().Nil(injector.Stop(context.Background()))
+	self.Equal([]string{}, events)
+}
+
+func (self *LifecycleTests) TestStartError() {
+	events := []string{}
+	injector, err := InjectorOf(lifecycleTestModule{events: &events, startErr: testError})
+	self.Require().Nil(err)
+	_ = injector.MustGet(new(int), Annotation2{})
+
+	err = injector.Start(context.Background())
+	self.Require().NotNil(err)
+	key := testKey(Annotation2{})
+	self.Equal(hookError{key: &key, starting: true, cause: testError}, err)
+	self.True(errors.Is(err, testError))
+	self.Equal([]string{"start base", "start dependent", "stop base"}, events)
+
+	self.Require().Nil(injector.Stop(context.Background()))
+	self.Equal([]string{"start base", "start dependent", "stop base"}, events)
+}
+
+func (self *LifecycleTests) TestTimeout() {
+	injector, err := InjectorOf(lifecycleTestModule{events: &[]string{}})
+	self.Require().Nil(err)
+	_ = injector.MustGet(new(int), Annotation3{})
+
+	err = injector.Start(context.Background())
+	self.Require().NotNil(err)
+	self.Equal(context.DeadlineExceeded, err.(hookError).cause)
+	self.True(errors.Is(err, context.DeadlineExceeded))
+}
+
+func (self *LifecycleTests) TestTopLevelLifecycle() {
+	events := []string{}
+	injector, err := InjectorOf(lifecycleTestModule{events: &events})
+	self.Require().Nil(err)
+	lifecycle := injector.MustGet(new(*Lifecycle), Builtin{}).(*Lifecycle)
+	lifecycle.OnStart(func(_ context.Context) error {
+		events = append(events, "start")
+		return nil
+	})
+
+	self.Require().Nil(injector.Start(context.Background()))
+	self.Equal([]string{"start"}, events)
+}
+
+func TestLifecycle(t *testing.T) {
+	suite.Run(t, new(LifecycleTests))
+}
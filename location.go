@@ -0,0 +1,48 @@
+package inject
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Get the code pointer of the function, or 0 if it is not a function.
+func functionPointer(function reflect.Value) uintptr {
+	if !function.IsValid() || function.Kind() != reflect.Func || function.IsNil() {
+		return 0
+	}
+	return function.Pointer()
+}
+
+// Get the file and the line where the function with the code pointer is defined, or an empty file name
+// if they are not known.
+func functionLocation(pointer uintptr) (string, int) {
+	if pointer == 0 {
+		return "", 0
+	}
+	runtimeFunction := runtime.FuncForPC(pointer)
+	if runtimeFunction == nil {
+		return "", 0
+	}
+	// Functions created with reflection and method values all have code pointers to
+	// generic trampolines in the reflect package.
+	if strings.HasPrefix(runtimeFunction.Name(), "reflect.") {
+		return "", 0
+	}
+	file, line := runtimeFunction.FileLine(runtimeFunction.Entry())
+	if file == "<autogenerated>" {
+		return "", 0
+	}
+	return file, line
+}
+
+// Get the code pointer of the module's method, which has the method's source location.
+func methodSource(moduleType reflect.Type, method reflect.Method) uintptr {
+	// Methods of pointer types with value receivers are autogenerated wrappers.
+	if moduleType.Kind() == reflect.Ptr {
+		if valueMethod, ok := moduleType.Elem().MethodByName(method.Name); ok {
+			return functionPointer(valueMethod.Func)
+		}
+	}
+	return functionPointer(method.Func)
+}
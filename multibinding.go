@@ -0,0 +1,239 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// A policy for elements of a set that are equal to other elements.
+// Elements of a set are ordered as their providers: in the order of modules passed to the injector,
+// and in the order of providers in each module, which is alphabetical for static modules.
+// Elements are equal if they are comparable and equal with `==`.
+type DuplicateElements int
+
+const (
+	// Keep all elements, including duplicates. This is the default.
+	AllowDuplicates DuplicateElements = iota
+	// Keep only the first of equal elements.
+	SkipDuplicates
+	// Fail to provide the set if it has equal elements, with a `DuplicateElementError`.
+	RejectDuplicates
+)
+
+// An error of a set with duplicate elements when its policy is `RejectDuplicates`.
+type DuplicateElementError struct {
+	// The key of the set.
+	Key Key
+	// The duplicate element.
+	Element interface{}
+}
+
+func (self DuplicateElementError) Error() string {
+	return fmt.Sprintf("Duplicate element %v of set %v", self.Element, self.Key)
+}
+
+// The module of providers of sets and maps, that the injector creates from providers of their
+// elements: `Injector.Module` returns it for keys of sets and maps.
+type SetModule struct{}
+
+// The providers of elements of a set.
+type setBinding struct {
+	elementType    reflect.Type
+	annotationType reflect.Type
+	// Keys of the elements in the order of their providers.
+	elements   []Key
+	duplicates DuplicateElements
+}
+
+// Test if the set of elements with the key already has an element of the same provider, for example
+// when the same module is combined into multiple modules. Such elements are only added once,
+// as the same providers of other keys. Providers of modules are the same if the modules are equal,
+// so equal values of a module type contribute their elements once, even if they are created separately.
+func hasSetElement(key Key, provider providerData, providers *providersData) bool {
+	set, ok := providers.sets[Key{valueType: reflect.SliceOf(key.valueType), annotationType: key.annotationType}]
+	if !ok {
+		return false
+	}
+	for _, elementKey := range set.elements {
+		if reflect.DeepEqual(providers.providers[elementKey].provider, provider.provider) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add an element with the key to its set, returning the key of the element.
+func addSetElement(key Key, duplicates DuplicateElements, providers *providersData) (Key, error) {
+	setKey := Key{valueType: reflect.SliceOf(key.valueType), annotationType: key.annotationType}
+	set, ok := providers.sets[setKey]
+	if !ok {
+		set = &setBinding{elementType: key.valueType, annotationType: key.annotationType}
+		providers.sets[setKey] = set
+	}
+	if duplicates != AllowDuplicates {
+		if set.duplicates != AllowDuplicates && set.duplicates != duplicates {
+			return Key{}, fmt.Errorf("Conflicting duplicate element policies for set %v", setKey)
+		}
+		set.duplicates = duplicates
+	}
+
+	key.element = len(set.elements) + 1
+	set.elements = append(set.elements, key)
+	return key, nil
+}
+
+// Add providers of sets that combine their elements.
+func buildSetProviders(providers *providersData) error {
+	setKeys := make([]Key, 0, len(providers.sets))
+	for setKey := range providers.sets {
+		setKeys = append(setKeys, setKey)
+	}
+	// Sort the keys to make errors deterministic.
+	sortKeys(setKeys)
+	for _, setKey := range setKeys {
+		set := providers.sets[setKey]
+		if _, ok := providers.providers[setKey]; ok {
+			return fmt.Errorf("Key %v is provided both by a provider and as a set", setKey)
+		}
+		providers.providers[setKey] = providerData{
+			provider:  set.provider(setKey),
+			arguments: set.elements,
+			hasError:  true,
+		}
+		providers.modules[setKey] = SetModule{}
+
+		mapType := getMapType(set.elementType)
+		if mapType == nil {
+			continue
+		}
+		mapKey := Key{valueType: mapType, annotationType: set.annotationType}
+		if _, ok := providers.providers[mapKey]; ok {
+			return fmt.Errorf("Key %v is provided both by a provider and as a map", mapKey)
+		}
+		modules := make([]Module, len(set.elements))
+		for index, elementKey := range set.elements {
+			modules[index] = providers.modules[elementKey]
+		}
+		providers.providers[mapKey] = providerData{
+			provider:  set.mapProvider(mapKey, modules),
+			arguments: set.elements,
+			hasError:  true,
+		}
+		providers.modules[mapKey] = SetModule{}
+	}
+	return nil
+}
+
+// Get the type of a function that takes the elements of the set and returns the value type
+// with the set's annotation and an error.
+func (self *setBinding) functionType(valueType reflect.Type) reflect.Type {
+	inputs := make([]reflect.Type, 0, len(self.elements)*2)
+	for range self.elements {
+		inputs = append(inputs, self.elementType, self.annotationType)
+	}
+	return reflect.FuncOf(
+		inputs,
+		[]reflect.Type{valueType, self.annotationType, globalErrorType},
+		false,
+	)
+}
+
+// Get the results of a function of the set's type that failed with the error.
+func (self *setBinding) failed(valueType reflect.Type, err error) []reflect.Value {
+	return []reflect.Value{
+		reflect.Zero(valueType),
+		reflect.Zero(self.annotationType),
+		reflect.ValueOf(&err).Elem(),
+	}
+}
+
+// Create the provider of the set: a function that takes its elements and returns them as a slice.
+func (self *setBinding) provider(setKey Key) reflect.Value {
+	return reflect.MakeFunc(self.functionType(setKey.valueType), func(arguments []reflect.Value) []reflect.Value {
+		set := reflect.MakeSlice(setKey.valueType, 0, len(self.elements))
+		seen := map[interface{}]bool{}
+		for index := 0; index < len(arguments); index += 2 {
+			element := arguments[index]
+			if self.duplicates != AllowDuplicates && element.Comparable() {
+				value := element.Interface()
+				if seen[value] && self.duplicates == RejectDuplicates {
+					return self.failed(setKey.valueType, DuplicateElementError{Key: setKey, Element: value})
+				}
+				if seen[value] {
+					continue
+				}
+				seen[value] = true
+			}
+			set = reflect.Append(set, element)
+		}
+		return []reflect.Value{set, reflect.Zero(self.annotationType), reflect.Zero(globalErrorType)}
+	})
+}
+
+// Create the provider of the map of a set of map entries: a function that takes the entries and
+// returns them as a map. The modules are the modules of the entries' providers.
+func (self *setBinding) mapProvider(mapKey Key, modules []Module) reflect.Value {
+	return reflect.MakeFunc(self.functionType(mapKey.valueType), func(arguments []reflect.Value) []reflect.Value {
+		result := reflect.MakeMapWithSize(mapKey.valueType, len(self.elements))
+		entryModules := map[interface{}]Module{}
+		for index := 0; index < len(arguments); index += 2 {
+			entryKey := arguments[index].Field(0)
+			if module, ok := entryModules[entryKey.Interface()]; ok {
+				return self.failed(mapKey.valueType, DuplicateMapKeyError{
+					Key:     mapKey,
+					MapKey:  entryKey.Interface(),
+					Modules: []Module{module, modules[index/2]},
+				})
+			}
+			entryModules[entryKey.Interface()] = modules[index/2]
+			result.SetMapIndex(entryKey, arguments[index].Field(1))
+		}
+		return []reflect.Value{result, reflect.Zero(self.annotationType), reflect.Zero(globalErrorType)}
+	})
+}
+
+// An entry of a map: sets of map entries are also provided as maps.
+// Keys are usually strings or annotations, with `Annotation` as the key type.
+type MapEntry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Create a map entry.
+func Entry[K comparable, V any](key K, value V) MapEntry[K, V] {
+	return MapEntry[K, V]{Key: key, Value: value}
+}
+
+// Implemented by all map entry types, for building maps without knowing K and V.
+type mapEntry interface {
+	mapType() reflect.Type
+}
+
+func (self MapEntry[K, V]) mapType() reflect.Type {
+	return reflect.TypeOf(map[K]V(nil))
+}
+
+var mapEntryInterfaceType = reflect.TypeOf((*mapEntry)(nil)).Elem()
+
+// Get the type of the map of entries of the type, if it is a map entry type.
+func getMapType(elementType reflect.Type) reflect.Type {
+	if elementType.Kind() != reflect.Struct || !elementType.Implements(mapEntryInterfaceType) {
+		return nil
+	}
+	return reflect.Zero(elementType).Interface().(mapEntry).mapType()
+}
+
+// An error of a map with two entries with the same key.
+type DuplicateMapKeyError struct {
+	// The key of the map.
+	Key Key
+	// The duplicate key of the entries.
+	MapKey interface{}
+	// The modules that provided the entries.
+	Modules []Module
+}
+
+func (self DuplicateMapKeyError) Error() string {
+	return fmt.Sprintf("Duplicate entries for key %#v of map %v in modules %T and %T",
+		self.MapKey, self.Key, self.Modules[0], self.Modules[1])
+}
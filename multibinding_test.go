@@ -0,0 +1,328 @@
+package inject
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MultibindingTests struct {
+	suite.Suite
+}
+
+type setTestModule1 struct {
+	calls *int
+}
+
+func (self setTestModule1) ProvideIntoValuesSecond() (int, Annotation1) {
+	return 2, Annotation1{}
+}
+
+func (self setTestModule1) ProvideCachedIntoValuesFirst() (int, Annotation1) {
+	*self.calls += 1
+	return 1, Annotation1{}
+}
+
+func (self setTestModule1) ProvideSum(values []int, _ Annotation1) (int, Annotation2) {
+	sum := 0
+	for _, value := range values {
+		sum += value
+	}
+	return sum, Annotation2{}
+}
+
+type setTestModule2 struct{}
+
+func (self setTestModule2) ProvideIntoValues() (int, Annotation1) {
+	return 3, Annotation1{}
+}
+
+// Not a provider of a set element: "Into" is not followed by an upper case letter.
+func (self setTestModule2) ProvideIntolerance() (int, Annotation1) {
+	return testValue, Annotation1{}
+}
+
+func elementKey(annotation Annotation, element int) Key {
+	key := testKey(annotation)
+	key.element = element + 1
+	return key
+}
+
+func (self *MultibindingTests) TestSet() {
+	calls := 0
+	injector, err := InjectorOf(setTestModule1{calls: &calls}, setTestModule2{})
+	self.Require().Nil(err)
+
+	values, err := Get[[]int, Annotation1](injector)
+	self.Nil(err)
+	self.Equal([]int{1, 2, 3}, values)
+	self.Equal(6, MustGet[int, Annotation2](injector))
+	self.Equal(1, calls)
+
+	self.Equal(testValue, MustGet[int, Annotation1](injector))
+}
+
+func (self *MultibindingTests) TestElementKeys() {
+	calls := 0
+	injector, err := InjectorOf(setTestModule1{calls: &calls}, setTestModule2{})
+	self.Require().Nil(err)
+
+	setKey := KeyOf(new([]int), Annotation1{})
+	self.Equal([]Dependency{
+		{Key: elementKey(Annotation1{}, 0)},
+		{Key: elementKey(Annotation1{}, 1)},
+		{Key: elementKey(Annotation1{}, 2)},
+	}, injector.Dependencies(setKey))
+	self.Equal("int/inject.Annotation1#2", elementKey(Annotation1{}, 2).String())
+	self.True(elementKey(Annotation1{}, 2).IsElement())
+	self.False(setKey.IsElement())
+	self.True(injector.IsCached(elementKey(Annotation1{}, 0)))
+	self.Equal(setTestModule2{}, injector.Module(elementKey(Annotation1{}, 2)))
+	self.Contains(injector.Keys(), elementKey(Annotation1{}, 1))
+	self.Equal(SetModule{}, injector.Module(setKey))
+	self.True(injector.IsSet(setKey))
+	self.False(injector.IsSet(elementKey(Annotation1{}, 0)))
+}
+
+func (self *MultibindingTests) TestSuggestions() {
+	injector, err := InjectorOf(setTestModule2{})
+	self.Require().Nil(err)
+	_, err = injector.Get(new(string), Annotation1{})
+	self.Require().IsType(MissingProviderError{}, err)
+	self.NotContains(err.(MissingProviderError).Suggestions, Suggestion{
+		Key:    elementKey(Annotation1{}, 0),
+		Reason: "different annotation",
+	})
+
+	injector, err = InjectorOf(setTestModule1{calls: new(int)})
+	self.Require().Nil(err)
+	_, err = injector.Get(new(int), Annotation1{})
+	self.Require().IsType(MissingProviderError{}, err)
+	self.Contains(err.(MissingProviderError).Suggestions, Suggestion{
+		Key:    elementKey(Annotation1{}, 0),
+		Reason: "element of a set: sets are provided as slices of their elements",
+	})
+}
+
+type setTestDuplicatesModule struct {
+	duplicates []DuplicateElements
+}
+
+func (self setTestDuplicatesModule) Providers() ([]Provider, error) {
+	providers := []Provider{}
+	for _, duplicates := range self.duplicates {
+		providers = append(providers, ValueProvider[string, Annotation1]("value").
+			IntoSet(true).
+			WithDuplicates(duplicates))
+	}
+	providers = append(providers, ValueProvider[string, Annotation1]("other").IntoSet(true))
+	return providers, nil
+}
+
+func (self *MultibindingTests) TestDuplicates() {
+	injector, err := InjectorOf(setTestDuplicatesModule{[]DuplicateElements{AllowDuplicates, AllowDuplicates}})
+	self.Require().Nil(err)
+	self.Equal([]string{"value", "value", "other"}, MustGet[[]string, Annotation1](injector))
+
+	injector, err = InjectorOf(setTestDuplicatesModule{[]DuplicateElements{SkipDuplicates, AllowDuplicates}})
+	self.Require().Nil(err)
+	self.Equal([]string{"value", "other"}, MustGet[[]string, Annotation1](injector))
+
+	injector, err = InjectorOf(setTestDuplicatesModule{[]DuplicateElements{AllowDuplicates, RejectDuplicates}})
+	self.Require().Nil(err)
+	_, err = Get[[]string, Annotation1](injector)
+	var duplicateErr DuplicateElementError
+	self.Require().True(errors.As(err, &duplicateErr))
+	self.Equal(DuplicateElementError{Key: KeyOf(new([]string), Annotation1{}), Element: "value"}, duplicateErr)
+	self.Equal("Duplicate element value of set []string/inject.Annotation1", duplicateErr.Error())
+}
+
+func (self *MultibindingTests) TestConflictingDuplicates() {
+	_, err := InjectorOf(setTestDuplicatesModule{[]DuplicateElements{SkipDuplicates, RejectDuplicates}})
+	self.Require().NotNil(err)
+	self.Equal("Conflicting duplicate element policies for set []string/inject.Annotation1", err.Error())
+}
+
+type setTestSharedModule struct{}
+
+func (self setTestSharedModule) ProvideIntoValues() (int, Annotation1) {
+	return 1, Annotation1{}
+}
+
+func (self setTestSharedModule) ProvideValue() (int, Annotation2) {
+	return 2, Annotation2{}
+}
+
+func (self *MultibindingTests) TestSharedModule() {
+	injector, err := InjectorOf(
+		CombineModules(setTestSharedModule{}, setTestModule2{}),
+		CombineModules(setTestSharedModule{}),
+	)
+	self.Require().Nil(err)
+	self.Equal([]int{1, 3}, MustGet[[]int, Annotation1](injector))
+	self.Equal(2, MustGet[int, Annotation2](injector))
+}
+
+type setTestFieldModule struct {
+	value int
+}
+
+func (self setTestFieldModule) ProvideIntoValues() (int, Annotation1) {
+	return self.value, Annotation1{}
+}
+
+func (self *MultibindingTests) TestEqualModules() {
+	injector, err := InjectorOf(setTestFieldModule{value: 1}, setTestFieldModule{value: 1})
+	self.Require().Nil(err)
+	self.Equal([]int{1}, MustGet[[]int, Annotation1](injector))
+
+	injector, err = InjectorOf(setTestFieldModule{value: 1}, setTestFieldModule{value: 2})
+	self.Require().Nil(err)
+	self.Equal([]int{1, 2}, MustGet[[]int, Annotation1](injector))
+}
+
+// Skips duplicate elements of providers of the module.
+type setTestSkipDuplicatesModule struct {
+	module Module
+}
+
+func (self setTestSkipDuplicatesModule) Providers() ([]Provider, error) {
+	providers, err := Providers(self.module)
+	if err != nil {
+		return nil, err
+	}
+	for index, provider := range providers {
+		if provider.IsIntoSet() {
+			providers[index] = provider.WithDuplicates(SkipDuplicates)
+		}
+	}
+	return providers, nil
+}
+
+type setTestOneModule struct{}
+
+func (self setTestOneModule) ProvideIntoValues() (int, Annotation1) {
+	return 1, Annotation1{}
+}
+
+func (self *MultibindingTests) TestStaticDuplicates() {
+	injector, err := InjectorOf(setTestSharedModule{}, setTestOneModule{})
+	self.Require().Nil(err)
+	self.Equal([]int{1, 1}, MustGet[[]int, Annotation1](injector))
+
+	injector, err = InjectorOf(setTestSkipDuplicatesModule{module: setTestSharedModule{}}, setTestOneModule{})
+	self.Require().Nil(err)
+	self.Equal([]int{1}, MustGet[[]int, Annotation1](injector))
+}
+
+type setTestProvidedModule struct{}
+
+func (self setTestProvidedModule) ProvideIntoValues() (int, Annotation1) {
+	return 1, Annotation1{}
+}
+
+func (self setTestProvidedModule) ProvideValues() ([]int, Annotation1) {
+	return nil, Annotation1{}
+}
+
+func (self *MultibindingTests) TestProvidedSet() {
+	_, err := InjectorOf(setTestProvidedModule{})
+	self.Require().NotNil(err)
+	self.Equal("Key []int/inject.Annotation1 is provided both by a provider and as a set", err.Error())
+}
+
+type setTestErrorModule struct{}
+
+func (self setTestErrorModule) ProvideIntoValues() (int, Annotation1, error) {
+	return 0, Annotation1{}, testError
+}
+
+func (self *MultibindingTests) TestElementError() {
+	injector, err := InjectorOf(setTestErrorModule{})
+	self.Require().Nil(err)
+	_, err = Get[[]int, Annotation1](injector)
+	self.Equal(ProviderFailedError{
+		Key:   elementKey(Annotation1{}, 0),
+		Path:  []Key{KeyOf(new([]int), Annotation1{}), elementKey(Annotation1{}, 0)},
+		Cause: testError,
+	}, err)
+}
+
+type mapTestModule1 struct{}
+
+func (self mapTestModule1) ProvideIntoHandlers() (MapEntry[string, int], Annotation1) {
+	return Entry("first", 1), Annotation1{}
+}
+
+func (self mapTestModule1) ProvideIntoAnnotated() (MapEntry[Annotation, int], Annotation1) {
+	return Entry[Annotation](Annotation2{}, 2), Annotation1{}
+}
+
+type mapTestModule2 struct {
+	key string
+}
+
+func (self mapTestModule2) ProvideIntoHandlers() (MapEntry[string, int], Annotation1) {
+	return Entry(self.key, 2), Annotation1{}
+}
+
+func (self mapTestModule2) ProvideIntoAnnotated() (MapEntry[Annotation, int], Annotation1) {
+	return Entry[Annotation](Annotation3{}, 3), Annotation1{}
+}
+
+func (self *MultibindingTests) TestMap() {
+	injector, err := InjectorOf(mapTestModule1{}, mapTestModule2{key: "second"})
+	self.Require().Nil(err)
+
+	self.Equal(map[string]int{"first": 1, "second": 2}, MustGet[map[string]int, Annotation1](injector))
+	self.Equal(map[Annotation]int{Annotation2{}: 2, Annotation3{}: 3},
+		MustGet[map[Annotation]int, Annotation1](injector))
+	// The entries are also provided as a set.
+	self.Equal([]MapEntry[string, int]{Entry("first", 1), Entry("second", 2)},
+		MustGet[[]MapEntry[string, int], Annotation1](injector))
+	entryType := reflect.TypeOf(MapEntry[string, int]{})
+	annotationType := reflect.TypeOf(Annotation1{})
+	self.Equal([]Dependency{
+		{Key: Key{valueType: entryType, annotationType: annotationType, element: 1}},
+		{Key: Key{valueType: entryType, annotationType: annotationType, element: 2}},
+	}, injector.Dependencies(KeyOf(new(map[string]int), Annotation1{})))
+	self.True(injector.IsSet(KeyOf(new(map[string]int), Annotation1{})))
+}
+
+func (self *MultibindingTests) TestMapDuplicateKey() {
+	injector, err := InjectorOf(mapTestModule1{}, mapTestModule2{key: "first"})
+	self.Require().Nil(err)
+
+	_, err = Get[map[string]int, Annotation1](injector)
+	var duplicateErr DuplicateMapKeyError
+	self.Require().True(errors.As(err, &duplicateErr))
+	self.Equal(DuplicateMapKeyError{
+		Key:     KeyOf(new(map[string]int), Annotation1{}),
+		MapKey:  "first",
+		Modules: []Module{mapTestModule1{}, mapTestModule2{key: "first"}},
+	}, duplicateErr)
+	self.Equal(`Duplicate entries for key "first" of map map[string]int/inject.Annotation1 `+
+		"in modules inject.mapTestModule1 and inject.mapTestModule2", duplicateErr.Error())
+}
+
+type mapTestProvidedModule struct{}
+
+func (self mapTestProvidedModule) ProvideIntoHandlers() (MapEntry[string, int], Annotation1) {
+	return Entry("first", 1), Annotation1{}
+}
+
+func (self mapTestProvidedModule) ProvideHandlers() (map[string]int, Annotation1) {
+	return nil, Annotation1{}
+}
+
+func (self *MultibindingTests) TestProvidedMap() {
+	_, err := InjectorOf(mapTestProvidedModule{})
+	self.Require().NotNil(err)
+	self.Equal("Key map[string]int/inject.Annotation1 is provided both by a provider and as a map", err.Error())
+}
+
+func TestMultibinding(t *testing.T) {
+	suite.Run(t, new(MultibindingTests))
+}
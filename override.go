@@ -0,0 +1,140 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// A special module that replaces providers of a base module with providers of other modules.
+type overrideModule struct {
+	base      Module
+	overrides []Module
+}
+
+// Create a module with providers of the base module, where providers of the overrides replace
+// providers of the same keys, for example to provide fakes in tests.
+// Sets and maps are replaced as a whole: providing an element of a set in the overrides replaces
+// all elements of the set in the base module.
+// All keys provided by the overrides must be provided by the base module, otherwise building
+// the injector fails.
+// Any of the modules can be combined, dynamic or rewritten modules and other overridden modules.
+func Override(base Module, overrides ...Module) Module {
+	return overrideModule{
+		base:      base,
+		overrides: overrides,
+	}
+}
+
+// Implement DynamicModule, so that overridden modules can be rewritten.
+func (self overrideModule) Providers() ([]Provider, error) {
+	moduleProviders, err := self.moduleProviders()
+	if err != nil {
+		return nil, err
+	}
+	providers := make([]Provider, len(moduleProviders))
+	for index, moduleProvider := range moduleProviders {
+		providers[index] = moduleProvider.provider
+	}
+	return providers, nil
+}
+
+// A provider with the module that has it.
+type moduleProvider struct {
+	module   Module
+	provider Provider
+}
+
+// Get providers of the module with the modules that have them.
+// Combined modules are flattened and overridden modules are resolved.
+func moduleProvidersOf(module Module) ([]moduleProvider, error) {
+	result := []moduleProvider{}
+	for _, module := range flattenModule(module) {
+		if override, ok := module.(overrideModule); ok {
+			moduleProviders, err := override.moduleProviders()
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, moduleProviders...)
+			continue
+		}
+
+		providers, err := Providers(module)
+		if err != nil {
+			return nil, err
+		}
+		for _, provider := range providers {
+			result = append(result, moduleProvider{module: module, provider: provider})
+		}
+	}
+	return result, nil
+}
+
+// Get providers of the base module that are not overridden, followed by providers of the overrides.
+func (self overrideModule) moduleProviders() ([]moduleProvider, error) {
+	base, err := moduleProvidersOf(self.base)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := moduleProvidersOf(CombineModules(self.overrides...))
+	if err != nil {
+		return nil, err
+	}
+
+	overridden := map[Key]bool{}
+	for _, override := range overrides {
+		for _, key := range bindingKeys(override.provider) {
+			overridden[key] = true
+		}
+	}
+	baseKeys := map[Key]bool{}
+	result := make([]moduleProvider, 0, len(base)+len(overrides))
+	for _, baseProvider := range base {
+		isOverridden := false
+		for _, key := range bindingKeys(baseProvider.provider) {
+			baseKeys[key] = true
+			isOverridden = isOverridden || overridden[key]
+		}
+		if !isOverridden {
+			result = append(result, baseProvider)
+		}
+	}
+
+	var errs errorList
+	reported := map[Key]bool{}
+	for _, override := range overrides {
+		keys := bindingKeys(override.provider)
+		matched := len(keys) == 0
+		for _, key := range keys {
+			matched = matched || baseKeys[key]
+		}
+		if !matched && !reported[keys[0]] {
+			reported[keys[0]] = true
+			errs = append(errs, fmt.Errorf(
+				"Override of key %v in module %#v does not match any key of the overridden module",
+				keys[0], override.module))
+		}
+	}
+	if err := errs.asError(); err != nil {
+		return nil, err
+	}
+	return append(result, overrides...), nil
+}
+
+// Get keys that the provider provides values for: its key, or the keys of the set and the map
+// that it provides an element of.
+// Invalid providers do not provide any keys, they are reported when the injector is built.
+func bindingKeys(provider Provider) []Key {
+	if !provider.IsValid() {
+		return nil
+	}
+	functionType := provider.Function().Type()
+	key := Key{valueType: functionType.Out(0), annotationType: functionType.Out(1)}
+	if !provider.IsIntoSet() {
+		return []Key{key}
+	}
+	keys := []Key{{valueType: reflect.SliceOf(key.valueType), annotationType: key.annotationType}}
+	if mapType := getMapType(key.valueType); mapType != nil {
+		keys = append(keys, Key{valueType: mapType, annotationType: key.annotationType})
+	}
+	return keys
+}
@@ -0,0 +1,139 @@
+package inject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type OverrideTests struct {
+	suite.Suite
+}
+
+type overrideTestBaseModule struct{}
+
+func (self overrideTestBaseModule) ProvideValue() (int, Annotation1) {
+	return 1, Annotation1{}
+}
+
+func (self overrideTestBaseModule) ProvideDependent(value int, _ Annotation1) (int, Annotation2) {
+	return value * 10, Annotation2{}
+}
+
+func (self overrideTestBaseModule) ProvideIntoValues() (int, Annotation3) {
+	return 1, Annotation3{}
+}
+
+func (self overrideTestBaseModule) ProvideIntoValuesAgain() (int, Annotation3) {
+	return 2, Annotation3{}
+}
+
+type overrideTestModule struct{}
+
+func (self overrideTestModule) ProvideValue() (int, Annotation1) {
+	return 2, Annotation1{}
+}
+
+type overrideTestSetModule struct{}
+
+func (self overrideTestSetModule) ProvideIntoValues() (int, Annotation3) {
+	return 3, Annotation3{}
+}
+
+type overrideTestUnmatchedModule struct{}
+
+func (self overrideTestUnmatchedModule) ProvideValue() (string, Annotation1) {
+	return "", Annotation1{}
+}
+
+func (self *OverrideTests) TestOverride() {
+	injector, err := InjectorOf(Override(overrideTestBaseModule{}, overrideTestModule{}))
+	self.Require().Nil(err)
+	self.Equal(2, MustGet[int, Annotation1](injector))
+	self.Equal(20, MustGet[int, Annotation2](injector))
+	self.Equal([]int{1, 2}, MustGet[[]int, Annotation3](injector))
+	self.Equal(overrideTestModule{}, injector.Module(testKey(Annotation1{})))
+	self.Equal(overrideTestBaseModule{}, injector.Module(testKey(Annotation2{})))
+}
+
+func (self *OverrideTests) TestOverrideSet() {
+	injector, err := InjectorOf(Override(overrideTestBaseModule{}, overrideTestSetModule{}))
+	self.Require().Nil(err)
+	self.Equal([]int{3}, MustGet[[]int, Annotation3](injector))
+
+	injector, err = InjectorOf(Override(overrideTestBaseModule{}, overrideTestDynamicModule{}))
+	self.Require().Nil(err)
+	self.Equal([]int{4}, MustGet[[]int, Annotation3](injector))
+}
+
+type overrideTestDynamicModule struct{}
+
+func (self overrideTestDynamicModule) Providers() ([]Provider, error) {
+	return []Provider{ValueProvider[[]int, Annotation3]([]int{4})}, nil
+}
+
+type overrideTestStringModule struct{}
+
+func (self overrideTestStringModule) ProvideValue() (string, Annotation1) {
+	return "base", Annotation1{}
+}
+
+type overrideTestStringOverrideModule struct{}
+
+func (self overrideTestStringOverrideModule) Providers() ([]Provider, error) {
+	return []Provider{ValueProvider[string, Annotation1]("override")}, nil
+}
+
+func (self *OverrideTests) TestCombined() {
+	injector, err := InjectorOf(
+		Override(
+			CombineModules(overrideTestBaseModule{}, overrideTestStringModule{}),
+			CombineModules(overrideTestModule{}, overrideTestStringOverrideModule{}),
+		),
+	)
+	self.Require().Nil(err)
+	self.Equal(2, MustGet[int, Annotation1](injector))
+	self.Equal("override", MustGet[string, Annotation1](injector))
+}
+
+func (self *OverrideTests) TestNested() {
+	injector, err := InjectorOf(
+		Override(
+			Override(overrideTestBaseModule{}, overrideTestModule{}),
+			overrideTestDynamicModule{},
+		),
+		overrideTestStringModule{},
+	)
+	self.Require().Nil(err)
+	self.Equal(2, MustGet[int, Annotation1](injector))
+	self.Equal([]int{4}, MustGet[[]int, Annotation3](injector))
+	self.Equal("base", MustGet[string, Annotation1](injector))
+}
+
+func (self *OverrideTests) TestOnlyOverridesBase() {
+	_, err := InjectorOf(
+		Override(overrideTestBaseModule{}),
+		overrideTestModule{},
+	)
+	self.Require().NotNil(err)
+	self.Contains(err.Error(), "Duplicate providers")
+}
+
+func (self *OverrideTests) TestUnmatched() {
+	_, err := InjectorOf(Override(overrideTestBaseModule{}, overrideTestModule{}, overrideTestUnmatchedModule{}))
+	self.Require().NotNil(err)
+	self.Equal(
+		"Override of key string/inject.Annotation1 in module inject.overrideTestUnmatchedModule{} "+
+			"does not match any key of the overridden module",
+		err.Error())
+}
+
+func (self *OverrideTests) TestProviders() {
+	providers, err := Providers(Override(overrideTestBaseModule{}, overrideTestModule{}))
+	self.Require().Nil(err)
+	self.Equal(4, len(providers))
+}
+
+func TestOverride(t *testing.T) {
+	suite.Run(t, new(OverrideTests))
+}
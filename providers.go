@@ -1,47 +1,61 @@
 package inject
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 )
 
-type providerKey struct {
-	valueType      reflect.Type
-	annotationType reflect.Type
-}
-
 type providerData struct {
 	provider  reflect.Value
-	arguments []providerKey
+	arguments []Key
 	hasError  bool
 	cached    bool
+	scoped    bool
+	// Whether the provider takes the context of the resolution as the first input.
+	hasContext bool
 }
 
 type providersData struct {
 	// A map of provider keys to provider functions.
-	providers map[providerKey]providerData
+	providers map[Key]providerData
+	// A map of provider keys to modules that defined the providers.
+	modules map[Key]Module
+	// A map of provider keys to source locations of the providers, if they are known.
+	locations map[Key]sourceLocation
+	// A map of set keys to providers of their elements.
+	sets map[Key]*setBinding
+}
+
+type sourceLocation struct {
+	file string
+	line int
 }
 
 func buildProviders(module Module) (*providersData, error) {
 	providers := &providersData{
-		providers: map[providerKey]providerData{},
+		providers: map[Key]providerData{},
+		modules:   map[Key]Module{},
+		locations: map[Key]sourceLocation{},
+		sets:      map[Key]*setBinding{},
+	}
+	moduleProviders, err := moduleProvidersOf(module)
+	if err != nil {
+		return nil, err
 	}
-	for _, module := range flattenModule(module) {
-		dynamicProviders, err := Providers(module)
+	for _, moduleProvider := range moduleProviders {
+		err := buildProvidersFromDynamicProvider(moduleProvider.provider, moduleProvider.module, providers)
 		if err != nil {
 			return nil, err
 		}
-
-		for _, dynamicProvider := range dynamicProviders {
-			if err := buildProvidersFromDynamicProvider(dynamicProvider, providers); err != nil {
-				return nil, err
-			}
-		}
+	}
+	if err := buildSetProviders(providers); err != nil {
+		return nil, err
 	}
 	return providers, nil
 }
 
-func buildProvidersFromDynamicProvider(dynamicProvider Provider, providers *providersData) error {
+func buildProvidersFromDynamicProvider(dynamicProvider Provider, module Module, providers *providersData) error {
 	if !dynamicProvider.IsValid() {
 		return fmt.Errorf("%#v is an invalid provider.", dynamicProvider)
 	}
@@ -49,27 +63,45 @@ func buildProvidersFromDynamicProvider(dynamicProvider Provider, providers *prov
 	function := dynamicProvider.Function()
 	functionType := function.Type()
 
-	arguments := make([]providerKey, 0, functionType.NumIn()/2)
-	for inputIndex := 0; inputIndex < functionType.NumIn(); inputIndex += 2 {
+	firstInput := firstValueInput(functionType)
+	arguments := make([]Key, 0, functionType.NumIn()/2)
+	for inputIndex := firstInput; inputIndex < functionType.NumIn(); inputIndex += 2 {
 		valueInput := functionType.In(inputIndex)
 		annotationInput := functionType.In(inputIndex + 1)
-		arguments = append(arguments, providerKey{
+		arguments = append(arguments, Key{
 			valueType:      valueInput,
 			annotationType: annotationInput,
 		})
 	}
 
 	provider := providerData{
-		provider:  function,
-		arguments: arguments,
-		cached:    dynamicProvider.cached,
-		hasError:  functionType.NumOut() == 3,
+		provider:   function,
+		arguments:  arguments,
+		cached:     dynamicProvider.cached,
+		scoped:     dynamicProvider.scoped,
+		hasError:   functionType.NumOut() == 3,
+		hasContext: firstInput == 1,
 	}
-	key := providerKey{
+	key := Key{
 		valueType:      functionType.Out(0),
 		annotationType: functionType.Out(1),
 	}
-	if existingProvider, ok := providers.providers[key]; ok {
+	if isBuiltin(key) {
+		return fmt.Errorf("Key %v is provided by the injector and can not be provided by modules", key)
+	}
+	if provider.cached && provider.scoped {
+		return fmt.Errorf("Provider of %v can not be both cached and scoped", key)
+	}
+	if dynamicProvider.intoSet {
+		if hasSetElement(key, provider, providers) {
+			return nil
+		}
+		elementKey, err := addSetElement(key, dynamicProvider.duplicates, providers)
+		if err != nil {
+			return err
+		}
+		key = elementKey
+	} else if existingProvider, ok := providers.providers[key]; ok {
 		if !reflect.DeepEqual(existingProvider.provider, provider.provider) {
 			return fmt.Errorf(
 				"Duplicate providers for key {%v, %v}",
@@ -77,11 +109,16 @@ func buildProvidersFromDynamicProvider(dynamicProvider Provider, providers *prov
 		}
 	}
 	providers.providers[key] = provider
+	providers.modules[key] = module
+	if file, line := dynamicProvider.Location(); file != "" {
+		providers.locations[key] = sourceLocation{file: file, line: line}
+	}
 	return nil
 }
 
 var globalAnnotationType = reflect.TypeOf((*Annotation)(nil)).Elem()
 var globalErrorType = reflect.TypeOf((*error)(nil)).Elem()
+var globalContextType = reflect.TypeOf((*context.Context)(nil)).Elem()
 
 func isProvider(methodType reflect.Type) bool {
 	if methodType.Kind() != reflect.Func {
@@ -110,13 +147,24 @@ func hasAnnotationOutput(methodType reflect.Type) bool {
 	return isAnnotation(methodType.Out(1))
 }
 
+// Get the index of the first value input of the provider.
+// Providers can take the context of the resolution as an additional first input, before pairs of
+// values and annotations.
+func firstValueInput(methodType reflect.Type) int {
+	if methodType.NumIn()%2 == 1 && methodType.In(0) == globalContextType {
+		return 1
+	}
+	return 0
+}
+
 func hasInputsWithAnnotations(methodType reflect.Type) bool {
+	firstInput := firstValueInput(methodType)
 	numberOfInputs := methodType.NumIn()
-	if numberOfInputs%2 != 0 {
+	if (numberOfInputs-firstInput)%2 != 0 {
 		return false
 	}
 
-	for inputIndex := 1; inputIndex < numberOfInputs; inputIndex += 2 {
+	for inputIndex := firstInput + 1; inputIndex < numberOfInputs; inputIndex += 2 {
 		annotationInput := methodType.In(inputIndex)
 		if !isAnnotation(annotationInput) {
 			return false
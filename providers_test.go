@@ -1,6 +1,7 @@
 package inject
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"testing"
@@ -25,7 +26,7 @@ func (self testModuleWithProviders) Providers() ([]Provider, error) {
 
 func (self *BuildProvidersTests) TestEmptyModule() {
 	providers := self.buildProviders(testModuleWithProviders{[]Provider{}})
-	self.Equal(map[providerKey]providerData{}, providers)
+	self.Equal(map[Key]providerData{}, providers)
 }
 
 func (self *BuildProvidersTests) TestNoArgumentsProvider() {
@@ -33,13 +34,13 @@ func (self *BuildProvidersTests) TestNoArgumentsProvider() {
 		return 0, testAnnotation1{}
 	}
 	providers := self.buildProviders(testModuleWithProviders{[]Provider{NewProvider(function)}})
-	self.Equal(map[providerKey]providerData{
+	self.Equal(map[Key]providerData{
 		{
 			valueType:      reflect.TypeOf(int(0)),
 			annotationType: reflect.TypeOf(testAnnotation1{}),
 		}: {
 			provider:  reflect.ValueOf(function),
-			arguments: []providerKey{},
+			arguments: []Key{},
 		},
 	}, providers)
 }
@@ -49,13 +50,13 @@ func (self *BuildProvidersTests) TestReflectValueProvider() {
 		return 0, testAnnotation1{}
 	}
 	providers := self.buildProviders(testModuleWithProviders{[]Provider{NewProvider(reflect.ValueOf(function))}})
-	self.Equal(map[providerKey]providerData{
+	self.Equal(map[Key]providerData{
 		{
 			valueType:      reflect.TypeOf(int(0)),
 			annotationType: reflect.TypeOf(testAnnotation1{}),
 		}: {
 			provider:  reflect.ValueOf(function),
-			arguments: []providerKey{},
+			arguments: []Key{},
 		},
 	}, providers)
 }
@@ -69,13 +70,13 @@ func (self testStaticModule) Provide() (int, testAnnotation1) {
 func (self *BuildProvidersTests) TestStaticModule() {
 	module := testStaticModule{}
 	providers := self.buildProviders(module)
-	self.Equal(map[providerKey]providerData{
+	self.Equal(map[Key]providerData{
 		{
 			valueType:      reflect.TypeOf(int(0)),
 			annotationType: reflect.TypeOf(testAnnotation1{}),
 		}: {
 			provider:  reflect.ValueOf(module).MethodByName("Provide"),
-			arguments: []providerKey{},
+			arguments: []Key{},
 		},
 	}, providers)
 }
@@ -85,13 +86,13 @@ func (self *BuildProvidersTests) TestProviderWithArguments() {
 		return 0, testAnnotation1{}
 	}
 	providers := self.buildProviders(testModuleWithProviders{[]Provider{NewProvider(function)}})
-	self.Equal(map[providerKey]providerData{
+	self.Equal(map[Key]providerData{
 		{
 			valueType:      reflect.TypeOf(int(0)),
 			annotationType: reflect.TypeOf(testAnnotation1{}),
 		}: {
 			provider: reflect.ValueOf(function),
-			arguments: []providerKey{{
+			arguments: []Key{{
 				valueType:      reflect.TypeOf(bool(false)),
 				annotationType: reflect.TypeOf(testAnnotation2{}),
 			}},
@@ -99,18 +100,57 @@ func (self *BuildProvidersTests) TestProviderWithArguments() {
 	}, providers)
 }
 
+func (self *BuildProvidersTests) TestProviderWithContext() {
+	function := func(_ context.Context, _ bool, _ testAnnotation2) (int, testAnnotation1) {
+		return 0, testAnnotation1{}
+	}
+	providers := self.buildProviders(testModuleWithProviders{[]Provider{NewProvider(function)}})
+	self.Equal(map[Key]providerData{
+		{
+			valueType:      reflect.TypeOf(int(0)),
+			annotationType: reflect.TypeOf(testAnnotation1{}),
+		}: {
+			provider: reflect.ValueOf(function),
+			arguments: []Key{{
+				valueType:      reflect.TypeOf(bool(false)),
+				annotationType: reflect.TypeOf(testAnnotation2{}),
+			}},
+			hasContext: true,
+		},
+	}, providers)
+}
+
+func (self *BuildProvidersTests) TestProviderWithContextArgument() {
+	function := func(_ context.Context, _ testAnnotation2) (int, testAnnotation1) {
+		return 0, testAnnotation1{}
+	}
+	providers := self.buildProviders(testModuleWithProviders{[]Provider{NewProvider(function)}})
+	self.Equal(map[Key]providerData{
+		{
+			valueType:      reflect.TypeOf(int(0)),
+			annotationType: reflect.TypeOf(testAnnotation1{}),
+		}: {
+			provider: reflect.ValueOf(function),
+			arguments: []Key{{
+				valueType:      reflect.TypeOf((*context.Context)(nil)).Elem(),
+				annotationType: reflect.TypeOf(testAnnotation2{}),
+			}},
+		},
+	}, providers)
+}
+
 func (self *BuildProvidersTests) TestProviderWithError() {
 	function := func() (int, testAnnotation1, error) {
 		return 0, testAnnotation1{}, nil
 	}
 	providers := self.buildProviders(testModuleWithProviders{[]Provider{NewProvider(function)}})
-	self.Equal(map[providerKey]providerData{
+	self.Equal(map[Key]providerData{
 		{
 			valueType:      reflect.TypeOf(int(0)),
 			annotationType: reflect.TypeOf(testAnnotation1{}),
 		}: {
 			provider:  reflect.ValueOf(function),
-			arguments: []providerKey{},
+			arguments: []Key{},
 			hasError:  true,
 		},
 	}, providers)
@@ -121,13 +161,13 @@ func (self *BuildProvidersTests) TestCachedProvider() {
 		return 0, testAnnotation1{}
 	}
 	providers := self.buildProviders(testModuleWithProviders{[]Provider{NewProvider(function).Cached(true)}})
-	self.Equal(map[providerKey]providerData{
+	self.Equal(map[Key]providerData{
 		{
 			valueType:      reflect.TypeOf(int(0)),
 			annotationType: reflect.TypeOf(testAnnotation1{}),
 		}: {
 			provider:  reflect.ValueOf(function),
-			arguments: []providerKey{},
+			arguments: []Key{},
 			cached:    true,
 		},
 	}, providers)
@@ -144,20 +184,20 @@ func (self *BuildProvidersTests) TestMultipleModules() {
 		testModuleWithProviders{[]Provider{NewProvider(function1)}},
 		testModuleWithProviders{[]Provider{NewProvider(function2)}},
 	))
-	self.Equal(map[providerKey]providerData{
+	self.Equal(map[Key]providerData{
 		{
 			valueType:      reflect.TypeOf(int(0)),
 			annotationType: reflect.TypeOf(testAnnotation1{}),
 		}: {
 			provider:  reflect.ValueOf(function1),
-			arguments: []providerKey{},
+			arguments: []Key{},
 		},
 		{
 			valueType:      reflect.TypeOf(int(0)),
 			annotationType: reflect.TypeOf(testAnnotation2{}),
 		}: {
 			provider:  reflect.ValueOf(function2),
-			arguments: []providerKey{},
+			arguments: []Key{},
 		},
 	}, providers)
 }
@@ -170,13 +210,13 @@ func (self *BuildProvidersTests) TestDuplicatedProvider() {
 		NewProvider(function),
 		NewProvider(function),
 	}})
-	self.Equal(map[providerKey]providerData{
+	self.Equal(map[Key]providerData{
 		{
 			valueType:      reflect.TypeOf(int(0)),
 			annotationType: reflect.TypeOf(testAnnotation1{}),
 		}: {
 			provider:  reflect.ValueOf(function),
-			arguments: []providerKey{},
+			arguments: []Key{},
 		},
 	}, providers)
 }
@@ -223,7 +263,7 @@ func (self *BuildProvidersTests) TestProvidersError() {
 	self.Equal(testError, err)
 }
 
-func (self *BuildProvidersTests) buildProviders(module Module) map[providerKey]providerData {
+func (self *BuildProvidersTests) buildProviders(module Module) map[Key]providerData {
 	providers, err := buildProviders(module)
 	self.Require().Nil(err)
 	return providers.providers
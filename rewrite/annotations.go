@@ -1,4 +1,4 @@
-/// rewrite provides tools to dynamically transform modules to chenge their providers using reflection.
+// rewrite provides tools to dynamically transform modules to chenge their providers using reflection.
 package rewrite
 
 import (
@@ -8,11 +8,11 @@ import (
 	"github.com/monnoroch/go-inject"
 )
 
-/// Annotations mapping: a map from annotations to be replaced to annotations to replace them with.
+// Annotations mapping: a map from annotations to be replaced to annotations to replace them with.
 type AnnotationsMapping map[inject.Annotation]inject.Annotation
 
-/// Generate a module that takes all input module's providers and replaces specified annotations
-/// according to the `annotationsToRewrite` map.
+// Generate a module that takes all input module's providers and replaces specified annotations
+// according to the `annotationsToRewrite` map.
 func RewriteAnnotations(
 	module inject.Module,
 	annotationsToRewrite AnnotationsMapping,
@@ -48,8 +48,13 @@ func (self rewriteAnnotationsModule) Providers() ([]inject.Provider, error) {
 		function := provider.Function()
 		functionType := function.Type()
 
+		// Providers can take a context as an additional first input.
+		firstInput := functionType.NumIn() % 2
 		providerArgumentTypes := make([]reflect.Type, functionType.NumIn())
-		for inputIndex := 0; inputIndex < functionType.NumIn(); inputIndex += 2 {
+		if firstInput == 1 {
+			providerArgumentTypes[0] = functionType.In(0)
+		}
+		for inputIndex := firstInput; inputIndex < functionType.NumIn(); inputIndex += 2 {
 			annotationType := functionType.In(inputIndex + 1)
 			if rewrittenType, ok := annotationsToRewrite[annotationType]; ok {
 				annotationType = rewrittenType
@@ -69,7 +74,7 @@ func (self rewriteAnnotationsModule) Providers() ([]inject.Provider, error) {
 		if functionType.NumOut() == 3 {
 			returnTypes = append(returnTypes, reflect.TypeOf((*error)(nil)).Elem())
 		}
-		newProviders[index] = inject.NewProvider(reflect.MakeFunc(
+		newProviders[index] = provider.WithFunction(reflect.MakeFunc(
 			reflect.FuncOf(
 				providerArgumentTypes,
 				returnTypes,
@@ -77,7 +82,10 @@ func (self rewriteAnnotationsModule) Providers() ([]inject.Provider, error) {
 			),
 			func(arguments []reflect.Value) []reflect.Value {
 				newArguments := make([]reflect.Value, functionType.NumIn())
-				for inputIndex := 0; inputIndex < functionType.NumIn(); inputIndex += 2 {
+				if firstInput == 1 {
+					newArguments[0] = arguments[0]
+				}
+				for inputIndex := firstInput; inputIndex < functionType.NumIn(); inputIndex += 2 {
 					newArguments[inputIndex] = arguments[inputIndex]
 					newArguments[inputIndex+1] = reflect.Zero(functionType.In(inputIndex + 1))
 				}
@@ -85,7 +93,7 @@ func (self rewriteAnnotationsModule) Providers() ([]inject.Provider, error) {
 				resutls[1] = reflect.Zero(annotationType)
 				return resutls
 			},
-		)).Cached(provider.IsCached())
+		))
 	}
 	return newProviders, nil
 }
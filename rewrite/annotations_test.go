@@ -1,8 +1,10 @@
 package rewrite
 
 import (
+	"context"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -170,6 +172,35 @@ func (self *RewriteAnnotationsTests) TestReplaceProviderWithArguments() {
 	self.True(ok)
 }
 
+func (self *RewriteAnnotationsTests) TestReplaceProviderWithContext() {
+	type contextKey struct{}
+	providers := self.getProviders(
+		testModuleWithProviders{[]inject.Provider{inject.NewProvider(
+			func(ctx context.Context, value int, _ testAnnotation2) (int, testAnnotation1) {
+				return value + ctx.Value(contextKey{}).(int), testAnnotation1{}
+			},
+		)}},
+		AnnotationsMapping{
+			testAnnotation1{}: testAnnotation3{},
+			testAnnotation2{}: testAnnotation4{},
+		},
+	)
+	self.Equal(1, len(providers))
+	provider := providers[0]
+	self.True(provider.IsValid())
+
+	value, annotation := self.call(provider, []reflect.Value{
+		reflect.ValueOf(context.WithValue(context.Background(), contextKey{}, 1)),
+		reflect.ValueOf(int(testValue)),
+		reflect.ValueOf(testAnnotation4{}),
+	})
+
+	self.Equal(testValue+1, value)
+
+	_, ok := annotation.(testAnnotation3)
+	self.True(ok)
+}
+
 func (self *RewriteAnnotationsTests) TestReplaceProviderWithError() {
 	providers := self.getProviders(
 		testModuleWithProviders{[]inject.Provider{inject.NewProvider(
@@ -230,6 +261,18 @@ func (self *RewriteAnnotationsTests) TestCachedProvider() {
 	self.True(providers[0].IsCached())
 }
 
+func (self *RewriteAnnotationsTests) TestLocation() {
+	providers := self.getProviders(
+		testModuleWithProviders{[]inject.Provider{inject.NewProvider(func() (int, testAnnotation1) {
+			return 0, testAnnotation1{}
+		})}},
+		AnnotationsMapping{},
+	)
+	self.Equal(1, len(providers))
+	file, _ := providers[0].Location()
+	self.True(strings.HasSuffix(file, "annotations_test.go"))
+}
+
 type testErrorModule struct {
 	err error
 }
@@ -251,6 +294,33 @@ func (self *RewriteAnnotationsTests) TestInvalidProvider() {
 	self.Contains(err.Error(), "invalid provider")
 }
 
+func (self *RewriteAnnotationsTests) TestOverride() {
+	base := testModuleWithProviders{[]inject.Provider{
+		inject.ValueProvider[int, testAnnotation1](1),
+		inject.ValueProvider[string, testAnnotation1]("base"),
+	}}
+	override := testModuleWithProviders{[]inject.Provider{inject.ValueProvider[int, testAnnotation2](2)}}
+
+	// Overriding with a rewritten module.
+	injector, err := inject.InjectorOf(inject.Override(
+		base,
+		RewriteAnnotations(override, AnnotationsMapping{testAnnotation2{}: testAnnotation1{}}),
+	))
+	self.Require().Nil(err)
+	self.Equal(2, inject.MustGet[int, testAnnotation1](injector))
+
+	// Rewriting an overridden module.
+	injector, err = inject.InjectorOf(RewriteAnnotations(
+		inject.Override(base, testModuleWithProviders{[]inject.Provider{
+			inject.ValueProvider[int, testAnnotation1](3),
+		}}),
+		AnnotationsMapping{testAnnotation1{}: testAnnotation3{}},
+	))
+	self.Require().Nil(err)
+	self.Equal(3, inject.MustGet[int, testAnnotation3](injector))
+	self.Equal("base", inject.MustGet[string, testAnnotation3](injector))
+}
+
 func (self *RewriteAnnotationsTests) getProviders(
 	module inject.Module,
 	annotationsToRewrite AnnotationsMapping,
@@ -0,0 +1,116 @@
+package inject
+
+import (
+	"fmt"
+)
+
+// Create a scope of the injector, for example for each request.
+// The scope provides the same values as the injector. Values of scoped providers are cached in
+// the scope, so each scope has its own values, and values of cached providers are shared with
+// the injector and all its scopes. Cached providers can not depend on scoped values.
+// Closing the scope only stops values of scoped providers that it provided, the injector
+// stays open.
+func (self *Injector) NewScope() *Injector {
+	return &Injector{
+		providers: self.providers,
+		cache:     self.cache,
+		lifecycle: self.lifecycle,
+		options:   self.options,
+		parent:    self.parent,
+		inherited: self.inherited,
+		scope:     &valuesCache{},
+	}
+}
+
+// Get the injector without its scope, that provides values of cached providers: they are shared
+// with the injector and all its scopes, so their handles and injectors must outlive the scope.
+func (self *Injector) withoutScope() *Injector {
+	if self.scope == nil {
+		return self
+	}
+	unscoped := *self
+	unscoped.scope = nil
+	return &unscoped
+}
+
+// Get the value of a scoped key from the cache of the scope.
+func (self *Injector) getScoped(path *dependencyPath) (interface{}, error) {
+	for dependent := path.parent; dependent != nil; dependent = dependent.parent {
+		if self.providers.providers[dependent.key].cached {
+			return nil, ScopedDependencyError{Dependent: dependent.key, Key: path.key}
+		}
+	}
+	if self.scope == nil {
+		return nil, OutOfScopeError{Key: path.key, Path: path.keys()}
+	}
+	return self.scope.get(path, self.get)
+}
+
+// An error for a scoped key requested outside of a scope.
+type OutOfScopeError struct {
+	// The scoped key.
+	Key Key
+	// The resolution path from the requested key to the scoped key.
+	Path []Key
+}
+
+func (self OutOfScopeError) Error() string {
+	return fmt.Sprintf("Key %v is scoped and can only be provided in a scope%s", self.Key, formatPath(self.Path))
+}
+
+// An error for a cached key that depends on a scoped key.
+type ScopedDependencyError struct {
+	// The cached key.
+	Dependent Key
+	// The scoped key that the cached key depends on.
+	Key Key
+}
+
+func (self ScopedDependencyError) Error() string {
+	return fmt.Sprintf("Cached provider of %v depends on scoped key %v, which can change between scopes",
+		self.Dependent, self.Key)
+}
+
+// Check that no cached key depends on a scoped key, strictly or lazily, directly or through
+// not cached dependencies: its value would be shared by all scopes.
+func checkScopes(providers *providersData) error {
+	keys := make([]Key, 0, len(providers.providers))
+	for key, provider := range providers.providers {
+		if provider.cached {
+			keys = append(keys, key)
+		}
+	}
+	// Sort the keys to make errors deterministic.
+	sortKeys(keys)
+
+	var errs errorList
+	for _, key := range keys {
+		if scopedKey, ok := findScopedDependency(key, providers, map[Key]bool{}); ok {
+			errs = append(errs, ScopedDependencyError{Dependent: key, Key: scopedKey})
+		}
+	}
+	return errs.asError()
+}
+
+// Find a scoped key that the key depends on through not cached dependencies.
+func findScopedDependency(key Key, providers *providersData, visited map[Key]bool) (Key, bool) {
+	for _, argumentKey := range providers.providers[key].arguments {
+		dependencyKey := dependencyOn(argumentKey).Key
+		if visited[dependencyKey] {
+			continue
+		}
+		visited[dependencyKey] = true
+
+		dependency, ok := providers.providers[dependencyKey]
+		if !ok || dependency.cached {
+			continue
+		}
+		if dependency.scoped {
+			return dependencyKey, true
+		}
+		if scopedKey, ok := findScopedDependency(dependencyKey, providers, visited); ok {
+			return scopedKey, true
+		}
+	}
+	return Key{}, false
+}
@@ -0,0 +1,196 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ScopeTests struct {
+	suite.Suite
+}
+
+type scopeTestModule struct {
+	closed *[]string
+}
+
+func (self scopeTestModule) ProvideCachedConnection() (*testCloser, Annotation1) {
+	return &testCloser{name: "connection", closed: self.closed}, Annotation1{}
+}
+
+func (self scopeTestModule) ProvideScopedRequest(connection *testCloser, _ Annotation1) (*testCloser, Annotation2) {
+	return &testCloser{name: "request", closed: self.closed}, Annotation2{}
+}
+
+func (self scopeTestModule) ProvideHandler(request *testCloser, _ Annotation2) (*testCloser, Annotation3) {
+	return request, Annotation3{}
+}
+
+func (self *ScopeTests) injector(closed *[]string) *Injector {
+	injector, err := InjectorOf(scopeTestModule{closed: closed})
+	self.Require().Nil(err)
+	return injector
+}
+
+func (self *ScopeTests) TestScope() {
+	injector := self.injector(new([]string))
+	scope1 := injector.NewScope()
+	scope2 := injector.NewScope()
+
+	request1 := MustGet[*testCloser, Annotation2](scope1)
+	self.Same(request1, MustGet[*testCloser, Annotation2](scope1))
+	self.Same(request1, MustGet[*testCloser, Annotation3](scope1))
+	self.NotSame(request1, MustGet[*testCloser, Annotation2](scope2))
+	self.Same(MustGet[*testCloser, Annotation1](injector), MustGet[*testCloser, Annotation1](scope1))
+	self.Same(MustGet[*testCloser, Annotation1](scope1), MustGet[*testCloser, Annotation1](scope2))
+	self.True(injector.IsScoped(KeyOf(new(*testCloser), Annotation2{})))
+	self.False(injector.IsScoped(KeyOf(new(*testCloser), Annotation1{})))
+}
+
+func (self *ScopeTests) TestOutOfScope() {
+	injector := self.injector(new([]string))
+	_, err := Get[*testCloser, Annotation3](injector)
+	self.Equal(OutOfScopeError{
+		Key:  KeyOf(new(*testCloser), Annotation2{}),
+		Path: []Key{KeyOf(new(*testCloser), Annotation3{}), KeyOf(new(*testCloser), Annotation2{})},
+	}, err)
+	self.Equal("Key *inject.testCloser/inject.Annotation2 is scoped and can only be provided in a scope "+
+		"(resolution path: *inject.testCloser/inject.Annotation3 -> *inject.testCloser/inject.Annotation2)",
+		err.Error())
+}
+
+func (self *ScopeTests) TestClose() {
+	closed := []string{}
+	injector := self.injector(&closed)
+	scope := injector.NewScope()
+	MustGet[*testCloser, Annotation3](scope)
+	self.Require().Nil(scope.Close(context.Background()))
+	self.Equal([]string{"request"}, closed)
+
+	_, err := Get[*testCloser, Annotation3](scope)
+	self.Equal(injectorClosedError, err)
+	_, err = Get[*testCloser, Annotation3](injector.NewScope())
+	self.Nil(err)
+
+	self.Require().Nil(injector.Close(context.Background()))
+	self.Equal([]string{"request", "connection"}, closed)
+}
+
+type scopeTestServer struct {
+	handle   Handle[string]
+	injector *Injector
+}
+
+type scopeTestHandleModule struct{}
+
+func (self scopeTestHandleModule) ProvideValue() (string, Annotation1) {
+	return "value", Annotation1{}
+}
+
+func (self scopeTestHandleModule) ProvideCachedServer(
+	handle Handle[string], _ Annotation1,
+	injector *Injector, _ Builtin,
+) (*scopeTestServer, Annotation1) {
+	return &scopeTestServer{handle: handle, injector: injector}, Annotation1{}
+}
+
+func (self *ScopeTests) TestSingletonOutlivesScope() {
+	injector, err := InjectorOf(scopeTestHandleModule{})
+	self.Require().Nil(err)
+	scope := injector.NewScope()
+	server := MustGet[*scopeTestServer, Annotation1](scope)
+	self.Require().Nil(scope.Close(context.Background()))
+
+	self.Same(server, MustGet[*scopeTestServer, Annotation1](injector))
+	value, err := server.handle.Get()
+	self.Nil(err)
+	self.Equal("value", value)
+	value, err = Get[string, Annotation1](server.injector)
+	self.Nil(err)
+	self.Equal("value", value)
+}
+
+type scopeTestSingletonModule struct{}
+
+func (self scopeTestSingletonModule) ProvideScopedRequest() (int, Annotation1) {
+	return testValue, Annotation1{}
+}
+
+func (self scopeTestSingletonModule) ProvideHandler(request func() int, _ Annotation1) (int, Annotation2) {
+	return request(), Annotation2{}
+}
+
+func (self scopeTestSingletonModule) ProvideCachedServer(handler int, _ Annotation2) (int, Annotation3) {
+	return handler, Annotation3{}
+}
+
+func (self *ScopeTests) TestSingletonDependsOnScoped() {
+	expected := ScopedDependencyError{Dependent: testKey(Annotation3{}), Key: testKey(Annotation1{})}
+	_, err := InjectorOf(scopeTestSingletonModule{})
+	self.Require().NotNil(err)
+	self.Equal(expected, err)
+	self.Equal("Cached provider of int/inject.Annotation3 depends on scoped key int/inject.Annotation1, "+
+		"which can change between scopes", err.Error())
+
+	err = ValidateGraph(scopeTestSingletonModule{})
+	self.Require().NotNil(err)
+	self.Equal(expected, err)
+}
+
+type scopeTestInjectorModule struct{}
+
+func (self scopeTestInjectorModule) ProvideScopedRequest() (int, Annotation1) {
+	return testValue, Annotation1{}
+}
+
+func (self scopeTestInjectorModule) ProvideCachedServer(injector *Injector, _ Builtin) (int, Annotation2, error) {
+	value, err := Get[int, Annotation1](injector)
+	return value, Annotation2{}, err
+}
+
+func (self *ScopeTests) TestSingletonGetsScoped() {
+	injector, err := InjectorOf(scopeTestInjectorModule{})
+	self.Require().Nil(err)
+	_, err = Get[int, Annotation2](injector.NewScope())
+	scopedDependencyErr := ScopedDependencyError{}
+	self.Require().True(errors.As(err, &scopedDependencyErr))
+	self.Equal(ScopedDependencyError{Dependent: testKey(Annotation2{}), Key: testKey(Annotation1{})},
+		scopedDependencyErr)
+}
+
+type scopeTestCachedAndScopedModule struct{}
+
+func (self scopeTestCachedAndScopedModule) Providers() ([]Provider, error) {
+	return []Provider{ValueProvider[int, Annotation1](testValue).Cached(true).Scoped(true)}, nil
+}
+
+func (self *ScopeTests) TestCachedAndScoped() {
+	_, err := InjectorOf(scopeTestCachedAndScopedModule{})
+	self.Require().NotNil(err)
+	self.Equal("Provider of int/inject.Annotation1 can not be both cached and scoped", err.Error())
+}
+
+type scopeTestChildModule struct{}
+
+func (self scopeTestChildModule) ProvideValue() (string, Annotation1) {
+	return "child", Annotation1{}
+}
+
+func (self *ScopeTests) TestChild() {
+	injector := self.injector(new([]string))
+	child, err := injector.Child(scopeTestChildModule{})
+	self.Require().Nil(err)
+	scope := child.NewScope()
+
+	// The handler of the parent depends on the scoped request, so the child provides it
+	// in its scope, but the connection is still shared.
+	self.Same(MustGet[*testCloser, Annotation2](scope), MustGet[*testCloser, Annotation3](scope))
+	self.Same(MustGet[*testCloser, Annotation1](injector), MustGet[*testCloser, Annotation1](scope))
+	self.Equal("child", MustGet[string, Annotation1](scope))
+}
+
+func TestScope(t *testing.T) {
+	suite.Run(t, new(ScopeTests))
+}
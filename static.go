@@ -4,26 +4,35 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"unicode"
 )
 
-/// A wrapper type around a regular module that implements DynamicModule to make
-/// provider table generation code more uniform.
+// A wrapper type around a regular module that implements DynamicModule to make
+// provider table generation code more uniform.
 type staticProvidersModule struct {
 	module Module
 }
 
 const providerPrefix = "Provide"
 const cachedProviderPrefix = providerPrefix + "Cached"
+const scopedProviderPrefix = providerPrefix + "Scoped"
+
+// The prefix of providers of set elements after any of the prefixes above.
+const intoSetPrefix = "Into"
 
 func (self staticProvidersModule) Providers() ([]Provider, error) {
-	providerKeys := map[providerKey]struct{}{}
+	providerKeys := map[Key]struct{}{}
 	providers := []Provider{}
 	moduleValue := reflect.ValueOf(self.module)
 	moduleType := moduleValue.Type()
 	for methodIndex := 0; methodIndex < moduleValue.NumMethod(); methodIndex += 1 {
 		method := moduleValue.Method(methodIndex)
 		methodDefinition := moduleType.Method(methodIndex)
-		provider := NewProvider(method).Cached(strings.HasPrefix(methodDefinition.Name, cachedProviderPrefix))
+		provider := NewProvider(method).
+			Cached(strings.HasPrefix(methodDefinition.Name, cachedProviderPrefix)).
+			Scoped(strings.HasPrefix(methodDefinition.Name, scopedProviderPrefix)).
+			IntoSet(isIntoSetProvider(methodDefinition.Name))
+		provider.source = methodSource(moduleType, methodDefinition)
 		if !strings.HasPrefix(methodDefinition.Name, providerPrefix) || !provider.IsValid() {
 			return nil, fmt.Errorf(
 				"%#v is not a module: it has an invalid provider %#v.",
@@ -31,10 +40,14 @@ func (self staticProvidersModule) Providers() ([]Provider, error) {
 		}
 
 		methodType := method.Type()
-		key := providerKey{
+		key := Key{
 			valueType:      methodType.Out(0),
 			annotationType: methodType.Out(1),
 		}
+		if provider.IsIntoSet() {
+			providers = append(providers, provider)
+			continue
+		}
 		if _, ok := providerKeys[key]; ok {
 			return nil, fmt.Errorf("Duplicate providers for key %v in module %#v", key, self.module)
 		}
@@ -44,3 +57,16 @@ func (self staticProvidersModule) Providers() ([]Provider, error) {
 
 	return providers, nil
 }
+
+// Test if the provider method provides an element of a set: its name starts with
+// `ProvideInto`, `ProvideCachedInto` or `ProvideScopedInto` followed by an upper case letter.
+func isIntoSetProvider(name string) bool {
+	name = strings.TrimPrefix(name, cachedProviderPrefix)
+	name = strings.TrimPrefix(name, scopedProviderPrefix)
+	name = strings.TrimPrefix(name, providerPrefix)
+	if !strings.HasPrefix(name, intoSetPrefix) {
+		return false
+	}
+	name = strings.TrimPrefix(name, intoSetPrefix)
+	return name != "" && unicode.IsUpper([]rune(name)[0])
+}
@@ -26,7 +26,9 @@ func (self testProviderModule) Provide() (int32, int64) {
 func (self *StaticProvidersTests) TestProvider() {
 	module := testProviderModule{}
 	providers := self.getProviders(module)
-	self.Equal([]Provider{NewProvider(reflect.ValueOf(module).MethodByName("Provide"))}, providers)
+	provider := NewProvider(reflect.ValueOf(module).MethodByName("Provide"))
+	provider.source = staticMethodSource(module, "Provide")
+	self.Equal([]Provider{provider}, providers)
 }
 
 type testCachedProviderModule struct{}
@@ -38,7 +40,23 @@ func (self testCachedProviderModule) ProvideCached() (int32, int64) {
 func (self *StaticProvidersTests) TestCachedProvider() {
 	module := testCachedProviderModule{}
 	providers := self.getProviders(module)
-	self.Equal([]Provider{NewProvider(reflect.ValueOf(module).MethodByName("ProvideCached")).Cached(true)}, providers)
+	provider := NewProvider(reflect.ValueOf(module).MethodByName("ProvideCached")).Cached(true)
+	provider.source = staticMethodSource(module, "ProvideCached")
+	self.Equal([]Provider{provider}, providers)
+}
+
+type testScopedProviderModule struct{}
+
+func (self testScopedProviderModule) ProvideScoped() (int32, int64) {
+	return 0, 0
+}
+
+func (self *StaticProvidersTests) TestScopedProvider() {
+	module := testScopedProviderModule{}
+	providers := self.getProviders(module)
+	provider := NewProvider(reflect.ValueOf(module).MethodByName("ProvideScoped")).Scoped(true)
+	provider.source = staticMethodSource(module, "ProvideScoped")
+	self.Equal([]Provider{provider}, providers)
 }
 
 type testBadMethodNameModule struct{}
@@ -0,0 +1,97 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// A provided key that is similar to a key without a provider, so it might be the one that
+// was meant.
+type Suggestion struct {
+	Key Key
+	// How the key differs from the one without a provider.
+	Reason string
+}
+
+func (self Suggestion) String() string {
+	return fmt.Sprintf("%v (%s)", self.Key, self.Reason)
+}
+
+// Find provided keys that are similar to the key: keys of the same type with other annotations
+// and keys with the same annotation whose type is a pointer to the key's type or the other way
+// around, implements the key's interface type or the other way around, or is a function that
+// returns the key's type or the other way around.
+func (self *providersData) suggestions(key Key) []Suggestion {
+	var suggestions []Suggestion
+	for _, providedKey := range self.keysWithBuiltins() {
+		if providedKey == key {
+			continue
+		}
+		if reason := suggestionReason(key, providedKey); reason != "" {
+			suggestions = append(suggestions, Suggestion{Key: providedKey, Reason: reason})
+		}
+	}
+	// Sort the suggestions to make errors deterministic.
+	sort.Slice(suggestions, func(i int, j int) bool {
+		return suggestions[i].Key.String() < suggestions[j].Key.String()
+	})
+	return suggestions
+}
+
+func (self *providersData) keysWithBuiltins() []Key {
+	keys := make([]Key, 0, len(self.providers)+2)
+	for key := range self.providers {
+		keys = append(keys, key)
+	}
+	return append(keys, injectorKey, lifecycleKey)
+}
+
+// Get the reason why the provided key might be the one that was meant instead of the key,
+// or an empty string if the keys are not similar.
+func suggestionReason(key Key, providedKey Key) string {
+	if providedKey.IsElement() {
+		if key.valueType == providedKey.valueType && key.annotationType == providedKey.annotationType {
+			return "element of a set: sets are provided as slices of their elements"
+		}
+		return ""
+	}
+	if key.valueType == providedKey.valueType {
+		return "different annotation"
+	}
+	if key.annotationType != providedKey.annotationType {
+		return ""
+	}
+
+	valueType := key.valueType
+	providedType := providedKey.valueType
+	switch {
+	case providedType == reflect.PtrTo(valueType):
+		return "pointer to the type"
+	case valueType == reflect.PtrTo(providedType):
+		return "not a pointer"
+	case valueType.Kind() == reflect.Interface && providedType.Implements(valueType):
+		return "implements the interface"
+	case providedType.Kind() == reflect.Interface && valueType.Implements(providedType):
+		return "interface implemented by the type"
+	case getLazyArgumentType(key) == providedType:
+		return "not lazy: lazy values can only be provider arguments"
+	case getLazyArgumentType(providedKey) == valueType:
+		return "function returning the type: lazy arguments are not provided as values"
+	default:
+		return ""
+	}
+}
+
+// Format suggestions for error messages, if there are any.
+func formatSuggestions(suggestions []Suggestion) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	options := make([]string, len(suggestions))
+	for index, suggestion := range suggestions {
+		options[index] = suggestion.String()
+	}
+	return fmt.Sprintf("; did you mean %s?", strings.Join(options, " or "))
+}
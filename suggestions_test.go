@@ -0,0 +1,101 @@
+package inject
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SuggestionsTests struct {
+	suite.Suite
+}
+
+type suggestionsTestModule struct{}
+
+func (self suggestionsTestModule) ProvideValue() (int, Annotation1) {
+	return testValue, Annotation1{}
+}
+
+func (self suggestionsTestModule) ProvidePointer() (*int, Annotation2) {
+	return new(int), Annotation2{}
+}
+
+func (self suggestionsTestModule) ProvideStringer() (testStringer, Annotation3) {
+	return testStringer{}, Annotation3{}
+}
+
+func (self suggestionsTestModule) ProvideInterface() (fmt.Stringer, Annotation4) {
+	return testStringer{}, Annotation4{}
+}
+
+type testStringer struct{}
+
+func (self testStringer) String() string {
+	return ""
+}
+
+func (self *SuggestionsTests) TestDifferentAnnotation() {
+	self.Equal([]Suggestion{
+		{Key: testKey(Annotation1{}), Reason: "different annotation"},
+	}, self.suggestions(new(int), Annotation4{}))
+}
+
+func (self *SuggestionsTests) TestPointer() {
+	self.Equal([]Suggestion{
+		{Key: KeyOf(new(*int), Annotation2{}), Reason: "pointer to the type"},
+		{Key: testKey(Annotation1{}), Reason: "different annotation"},
+	}, self.suggestions(new(int), Annotation2{}))
+	self.Equal([]Suggestion{
+		{Key: KeyOf(new(*int), Annotation2{}), Reason: "different annotation"},
+		{Key: testKey(Annotation1{}), Reason: "not a pointer"},
+	}, self.suggestions(new(*int), Annotation1{}))
+}
+
+func (self *SuggestionsTests) TestInterface() {
+	self.Equal([]Suggestion{
+		{Key: KeyOf(new(fmt.Stringer), Annotation4{}), Reason: "different annotation"},
+		{Key: KeyOf(new(testStringer), Annotation3{}), Reason: "implements the interface"},
+	}, self.suggestions(new(fmt.Stringer), Annotation3{}))
+	self.Equal([]Suggestion{
+		{Key: KeyOf(new(fmt.Stringer), Annotation4{}), Reason: "interface implemented by the type"},
+		{Key: KeyOf(new(testStringer), Annotation3{}), Reason: "different annotation"},
+	}, self.suggestions(new(testStringer), Annotation4{}))
+}
+
+func (self *SuggestionsTests) TestLazy() {
+	self.Equal([]Suggestion{
+		{Key: testKey(Annotation1{}), Reason: "not lazy: lazy values can only be provider arguments"},
+	}, self.suggestions(new(func() int), Annotation1{}))
+}
+
+func (self *SuggestionsTests) TestBuiltin() {
+	self.Equal([]Suggestion{
+		{Key: injectorKey, Reason: "pointer to the type"},
+	}, self.suggestions(new(Injector), Builtin{}))
+}
+
+func (self *SuggestionsTests) TestNoSuggestions() {
+	self.Nil(self.suggestions(new(string), Annotation1{}))
+}
+
+func (self *SuggestionsTests) TestMessage() {
+	injector, err := InjectorOf(suggestionsTestModule{})
+	self.Require().Nil(err)
+	_, err = injector.Get(new(*int), Annotation1{})
+	self.Equal("No provider found for *int/inject.Annotation1; did you mean "+
+		"*int/inject.Annotation2 (different annotation) or int/inject.Annotation1 (not a pointer)?",
+		err.Error())
+}
+
+func (self *SuggestionsTests) suggestions(pointerToType interface{}, annotation Annotation) []Suggestion {
+	injector, err := InjectorOf(suggestionsTestModule{})
+	self.Require().Nil(err)
+	_, err = injector.Get(pointerToType, annotation)
+	self.Require().IsType(MissingProviderError{}, err)
+	return err.(MissingProviderError).Suggestions
+}
+
+func TestSuggestions(t *testing.T) {
+	suite.Run(t, new(SuggestionsTests))
+}
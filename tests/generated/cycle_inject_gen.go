@@ -0,0 +1,318 @@
+// Code generated by inject-gen. DO NOT EDIT.
+
+//go:build !inject_gen
+// +build !inject_gen
+
+package generated
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/monnoroch/go-inject"
+)
+
+// CycleInjector provides values of the modules CycleModule without reflection.
+type CycleInjector struct {
+	module0 CycleModule
+
+	// The cached value of generated.Deferred/generated.Later.
+	cache0 struct {
+		sync.Mutex
+		done  bool
+		value Deferred
+		err   error
+	}
+
+	// The cached value of int/generated.Cycle.
+	cache1 struct {
+		sync.Mutex
+		done  bool
+		value int
+		err   error
+	}
+}
+
+// Create an injector from the modules.
+func NewCycleInjector(module0 CycleModule) *CycleInjector {
+	return &CycleInjector{module0: module0}
+}
+
+// Get the value of int/generated.CycleRoot.
+func (self *CycleInjector) CycleRoot(ctx context.Context) (int, error) {
+	return self.get3(ctx, nil, false)
+}
+
+// Get the value of int/generated.LaterRoot.
+func (self *CycleInjector) LaterRoot(ctx context.Context) (int, error) {
+	return self.get5(ctx, nil, false)
+}
+
+// Get the value of int/generated.RecursiveRoot.
+func (self *CycleInjector) RecursiveRoot(ctx context.Context) (int, error) {
+	return self.get7(ctx, nil, false)
+}
+
+// Get the cached value of generated.Deferred/generated.Later.
+func (self *CycleInjector) get0(ctx context.Context, parent *cycleInjectorPath, lazy bool) (value Deferred, err error) {
+	// Fail on cycles before locking the cache, which this resolution can be holding.
+	path, err := parent.child(0, lazy)
+	if err != nil {
+		return value, err
+	}
+	self.cache0.Lock()
+	defer self.cache0.Unlock()
+	if self.cache0.done {
+		return self.cache0.value, self.cache0.err
+	}
+	value, err = self.provide0(ctx, path)
+	// Values are not cached if the context was done before they were provided.
+	if _, canceled := err.(inject.CanceledError); !canceled {
+		self.cache0.value, self.cache0.err, self.cache0.done = value, err, true
+	}
+	return value, err
+}
+
+// Provide generated.Deferred/generated.Later with CycleModule.ProvideCachedLater.
+func (self *CycleInjector) provide0(ctx context.Context, path *cycleInjectorPath) (value Deferred, err error) {
+	// Lazy arguments called after the provider returns start new resolutions.
+	defer path.finish()
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(Deferred), Later{}), Cause: err}
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			lazyErr, ok := recovered.(cycleInjectorLazyError)
+			if !ok {
+				panic(recovered)
+			}
+			err = lazyErr.err
+		}
+	}()
+	value, _ = self.module0.ProvideCachedLater(func() int {
+		value, err := self.get4(ctx, path, true)
+		if err != nil {
+			panic(cycleInjectorLazyError{err: err})
+		}
+		return value
+	}, LaterDependency{})
+	return value, nil
+}
+
+// Get the cached value of int/generated.Cycle.
+func (self *CycleInjector) get1(ctx context.Context, parent *cycleInjectorPath, lazy bool) (value int, err error) {
+	// Fail on cycles before locking the cache, which this resolution can be holding.
+	path, err := parent.child(1, lazy)
+	if err != nil {
+		return value, err
+	}
+	self.cache1.Lock()
+	defer self.cache1.Unlock()
+	if self.cache1.done {
+		return self.cache1.value, self.cache1.err
+	}
+	value, err = self.provide1(ctx, path)
+	// Values are not cached if the context was done before they were provided.
+	if _, canceled := err.(inject.CanceledError); !canceled {
+		self.cache1.value, self.cache1.err, self.cache1.done = value, err, true
+	}
+	return value, err
+}
+
+// Provide int/generated.Cycle with CycleModule.ProvideCachedCycle.
+func (self *CycleInjector) provide1(ctx context.Context, path *cycleInjectorPath) (value int, err error) {
+	// Lazy arguments called after the provider returns start new resolutions.
+	defer path.finish()
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(int), Cycle{}), Cause: err}
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			lazyErr, ok := recovered.(cycleInjectorLazyError)
+			if !ok {
+				panic(recovered)
+			}
+			err = lazyErr.err
+		}
+	}()
+	value, _ = self.module0.ProvideCachedCycle(func() int {
+		value, err := self.get2(ctx, path, true)
+		if err != nil {
+			panic(cycleInjectorLazyError{err: err})
+		}
+		return value
+	}, CycleDependency{})
+	return value, nil
+}
+
+// Provide int/generated.CycleDependency with CycleModule.ProvideCycleDependency.
+func (self *CycleInjector) get2(ctx context.Context, parent *cycleInjectorPath, lazy bool) (value int, err error) {
+	path, err := parent.child(2, lazy)
+	if err != nil {
+		return value, err
+	}
+	argument0, err := self.get1(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(int), CycleDependency{}), Cause: err}
+	}
+	value, _ = self.module0.ProvideCycleDependency(argument0, Cycle{})
+	return value, nil
+}
+
+// Provide int/generated.CycleRoot with CycleModule.ProvideCycleRoot.
+func (self *CycleInjector) get3(ctx context.Context, parent *cycleInjectorPath, lazy bool) (value int, err error) {
+	path, err := parent.child(3, lazy)
+	if err != nil {
+		return value, err
+	}
+	argument0, err := self.get1(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(int), CycleRoot{}), Cause: err}
+	}
+	value, _ = self.module0.ProvideCycleRoot(argument0, Cycle{})
+	return value, nil
+}
+
+// Provide int/generated.LaterDependency with CycleModule.ProvideLaterDependency.
+func (self *CycleInjector) get4(ctx context.Context, parent *cycleInjectorPath, lazy bool) (value int, err error) {
+	path, err := parent.child(4, lazy)
+	if err != nil {
+		return value, err
+	}
+	argument0, err := self.get0(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(int), LaterDependency{}), Cause: err}
+	}
+	value, _ = self.module0.ProvideLaterDependency(argument0, Later{})
+	return value, nil
+}
+
+// Provide int/generated.LaterRoot with CycleModule.ProvideLaterRoot.
+func (self *CycleInjector) get5(ctx context.Context, parent *cycleInjectorPath, lazy bool) (value int, err error) {
+	path, err := parent.child(5, lazy)
+	if err != nil {
+		return value, err
+	}
+	argument0, err := self.get0(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(int), LaterRoot{}), Cause: err}
+	}
+	value, _ = self.module0.ProvideLaterRoot(argument0, Later{})
+	return value, nil
+}
+
+// Provide int/generated.Recursive with CycleModule.ProvideRecursive.
+func (self *CycleInjector) get6(ctx context.Context, parent *cycleInjectorPath, lazy bool) (value int, err error) {
+	path, err := parent.child(6, lazy)
+	if err != nil {
+		return value, err
+	}
+	// Lazy arguments called after the provider returns start new resolutions.
+	defer path.finish()
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(int), Recursive{}), Cause: err}
+	}
+	value, _, providerErr := self.module0.ProvideRecursive(func() (int, error) {
+		return self.get6(ctx, path, true)
+	}, Recursive{})
+	if providerErr != nil {
+		return value, inject.ProviderFailedError{Key: inject.KeyOf(new(int), Recursive{}), Cause: providerErr}
+	}
+	return value, nil
+}
+
+// Provide int/generated.RecursiveRoot with CycleModule.ProvideRecursiveRoot.
+func (self *CycleInjector) get7(ctx context.Context, parent *cycleInjectorPath, lazy bool) (value int, err error) {
+	path, err := parent.child(7, lazy)
+	if err != nil {
+		return value, err
+	}
+	argument0, err := self.get6(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(int), RecursiveRoot{}), Cause: err}
+	}
+	value, _ = self.module0.ProvideRecursiveRoot(argument0, Recursive{})
+	return value, nil
+}
+
+// A panic of a lazy argument of CycleInjector that failed to get its value.
+type cycleInjectorLazyError struct {
+	err error
+}
+
+// Keys of values of CycleInjector by the indices of their getters.
+var cycleInjectorKeys = [...]inject.Key{
+	inject.KeyOf(new(Deferred), Later{}),
+	inject.KeyOf(new(int), Cycle{}),
+	inject.KeyOf(new(int), CycleDependency{}),
+	inject.KeyOf(new(int), CycleRoot{}),
+	inject.KeyOf(new(int), LaterDependency{}),
+	inject.KeyOf(new(int), LaterRoot{}),
+	inject.KeyOf(new(int), Recursive{}),
+	inject.KeyOf(new(int), RecursiveRoot{}),
+}
+
+// A chain of keys of CycleInjector that are being resolved, from the requested key to the current one.
+type cycleInjectorPath struct {
+	// The index of the getter of the key.
+	key int
+	// Whether the key was requested by calling a lazy argument of the parent's provider.
+	lazy   bool
+	parent *cycleInjectorPath
+	// Set to 1 when the provider of the key returns.
+	finished int32
+}
+
+// Continue the path with the key, failing if resolving the key requires itself.
+func (self *cycleInjectorPath) child(key int, lazy bool) (*cycleInjectorPath, error) {
+	parent := self
+	if lazy && atomic.LoadInt32(&parent.finished) == 1 {
+		parent, lazy = nil, false
+	}
+	path := &cycleInjectorPath{key: key, lazy: lazy, parent: parent}
+	for start := parent; start != nil; start = start.parent {
+		if start.key != key {
+			continue
+		}
+		steps := []inject.CycleStep{}
+		for element := path; element != start; element = element.parent {
+			steps = append([]inject.CycleStep{{Key: cycleInjectorKeys[element.key], Lazy: element.lazy}}, steps...)
+		}
+		steps = append([]inject.CycleStep{{Key: cycleInjectorKeys[key]}}, steps...)
+		keys := []inject.Key{}
+		for element := path; element != nil; element = element.parent {
+			keys = append([]inject.Key{cycleInjectorKeys[element.key]}, keys...)
+		}
+		return nil, inject.CycleError{Steps: steps, Path: keys}
+	}
+	return path, nil
+}
+
+// Mark the provider of the path's key as returned.
+func (self *cycleInjectorPath) finish() {
+	atomic.StoreInt32(&self.finished, 1)
+}
@@ -0,0 +1,297 @@
+// Code generated by inject-gen. DO NOT EDIT.
+
+//go:build !inject_gen
+// +build !inject_gen
+
+package generated
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	"github.com/monnoroch/go-inject"
+)
+
+// DynamicInjector provides values of the modules *ServiceModule, RuntimeModule without reflection.
+// Values without providers in the modules are got from the runtime injector.
+type DynamicInjector struct {
+	module0  *ServiceModule
+	module1  RuntimeModule
+	injector *inject.Injector
+
+	// The cached value of int/generated.Derived.
+	cache1 struct {
+		sync.Mutex
+		done  bool
+		value int
+		err   error
+	}
+}
+
+// Create an injector from the modules and the runtime injector that provides values
+// without providers in them.
+func NewDynamicInjector(module0 *ServiceModule, module1 RuntimeModule, injector *inject.Injector) *DynamicInjector {
+	return &DynamicInjector{module0: module0, module1: module1, injector: injector}
+}
+
+// Get the value of bool/generated.Service.
+func (self *DynamicInjector) HasInjector(ctx context.Context) (bool, error) {
+	return self.get0(ctx, nil, false)
+}
+
+// Get the value of int/generated.Failing.
+func (self *DynamicInjector) Failing(ctx context.Context) (int, error) {
+	return self.get2(ctx, nil, false)
+}
+
+// Get the value of string/generated.Fallback.
+func (self *DynamicInjector) Fallback(ctx context.Context) (string, error) {
+	return self.get3(ctx, nil, false)
+}
+
+// Get the value of string/generated.Service.
+func (self *DynamicInjector) Service(ctx context.Context) (string, error) {
+	return self.get5(ctx, nil, false)
+}
+
+// Provide bool/generated.Service with RuntimeModule.ProvideHasInjector.
+func (self *DynamicInjector) get0(ctx context.Context, parent *dynamicInjectorPath, lazy bool) (value bool, err error) {
+	path, err := parent.child(0, lazy)
+	if err != nil {
+		return value, err
+	}
+	argument0, err := self.get6(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(bool), Service{}), Cause: err}
+	}
+	value, _ = self.module1.ProvideHasInjector(argument0, inject.Builtin{})
+	return value, nil
+}
+
+// Get the cached value of int/generated.Derived.
+func (self *DynamicInjector) get1(ctx context.Context, parent *dynamicInjectorPath, lazy bool) (value int, err error) {
+	// Fail on cycles before locking the cache, which this resolution can be holding.
+	path, err := parent.child(1, lazy)
+	if err != nil {
+		return value, err
+	}
+	self.cache1.Lock()
+	defer self.cache1.Unlock()
+	if self.cache1.done {
+		return self.cache1.value, self.cache1.err
+	}
+	value, err = self.provide1(ctx, path)
+	// Values are not cached if the context was done before they were provided.
+	if _, canceled := err.(inject.CanceledError); !canceled {
+		self.cache1.value, self.cache1.err, self.cache1.done = value, err, true
+	}
+	return value, err
+}
+
+// Provide int/generated.Derived with *ServiceModule.ProvideCachedDerived.
+func (self *DynamicInjector) provide1(ctx context.Context, path *dynamicInjectorPath) (value int, err error) {
+	argument0, err := self.get7(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(int), Derived{}), Cause: err}
+	}
+	value, _ = self.module0.ProvideCachedDerived(argument0, Base{})
+	return value, nil
+}
+
+// Provide int/generated.Failing with *ServiceModule.ProvideFailing.
+func (self *DynamicInjector) get2(ctx context.Context, parent *dynamicInjectorPath, lazy bool) (value int, err error) {
+	path, err := parent.child(2, lazy)
+	if err != nil {
+		return value, err
+	}
+	argument0, err := self.get1(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(int), Failing{}), Cause: err}
+	}
+	value, _, providerErr := self.module0.ProvideFailing(argument0, Derived{})
+	if providerErr != nil {
+		return value, inject.ProviderFailedError{Key: inject.KeyOf(new(int), Failing{}), Cause: providerErr}
+	}
+	return value, nil
+}
+
+// Provide string/generated.Fallback with *ServiceModule.ProvideFallback.
+func (self *DynamicInjector) get3(ctx context.Context, parent *dynamicInjectorPath, lazy bool) (value string, err error) {
+	path, err := parent.child(3, lazy)
+	if err != nil {
+		return value, err
+	}
+	// Lazy arguments called after the provider returns start new resolutions.
+	defer path.finish()
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(string), Fallback{}), Cause: err}
+	}
+	value, _ = self.module0.ProvideFallback(func() (string, error) {
+		return self.get4(ctx, path, true)
+	}, Lazy{})
+	return value, nil
+}
+
+// Provide string/generated.Lazy with *ServiceModule.ProvideLazy.
+func (self *DynamicInjector) get4(ctx context.Context, parent *dynamicInjectorPath, lazy bool) (value string, err error) {
+	path, err := parent.child(4, lazy)
+	if err != nil {
+		return value, err
+	}
+	argument0, err := self.get1(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(string), Lazy{}), Cause: err}
+	}
+	value, _, providerErr := self.module0.ProvideLazy(argument0, Derived{})
+	if providerErr != nil {
+		return value, inject.ProviderFailedError{Key: inject.KeyOf(new(string), Lazy{}), Cause: providerErr}
+	}
+	return value, nil
+}
+
+// Provide string/generated.Service with *ServiceModule.ProvideService.
+func (self *DynamicInjector) get5(ctx context.Context, parent *dynamicInjectorPath, lazy bool) (value string, err error) {
+	path, err := parent.child(5, lazy)
+	if err != nil {
+		return value, err
+	}
+	// Lazy arguments called after the provider returns start new resolutions.
+	defer path.finish()
+	argument0, err := self.get1(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	argument1, err := self.get8(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(string), Service{}), Cause: err}
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			lazyErr, ok := recovered.(dynamicInjectorLazyError)
+			if !ok {
+				panic(recovered)
+			}
+			err = lazyErr.err
+		}
+	}()
+	value, _ = self.module0.ProvideService(argument0, Derived{}, argument1, Endpoint{}, func() string {
+		value, err := self.get4(ctx, path, true)
+		if err != nil {
+			panic(dynamicInjectorLazyError{err: err})
+		}
+		return value
+	}, Lazy{})
+	return value, nil
+}
+
+// Get *inject.Injector/inject.Builtin from the runtime injector.
+func (self *DynamicInjector) get6(ctx context.Context, _ *dynamicInjectorPath, _ bool) (value *inject.Injector, err error) {
+	result, err := self.injector.GetContext(ctx, new(*inject.Injector), inject.Builtin{})
+	if err != nil {
+		return value, err
+	}
+	value, _ = result.(*inject.Injector)
+	return value, nil
+}
+
+// Get *int/generated.Base from the runtime injector.
+func (self *DynamicInjector) get7(ctx context.Context, _ *dynamicInjectorPath, _ bool) (value *int, err error) {
+	result, err := self.injector.GetContext(ctx, new(*int), Base{})
+	if err != nil {
+		return value, err
+	}
+	value, _ = result.(*int)
+	return value, nil
+}
+
+// Get *url.URL/generated.Endpoint from the runtime injector.
+func (self *DynamicInjector) get8(ctx context.Context, _ *dynamicInjectorPath, _ bool) (value *url.URL, err error) {
+	result, err := self.injector.GetContext(ctx, new(*url.URL), Endpoint{})
+	if err != nil {
+		return value, err
+	}
+	value, _ = result.(*url.URL)
+	return value, nil
+}
+
+// A panic of a lazy argument of DynamicInjector that failed to get its value.
+type dynamicInjectorLazyError struct {
+	err error
+}
+
+// Keys of values of DynamicInjector by the indices of their getters.
+var dynamicInjectorKeys = [...]inject.Key{
+	inject.KeyOf(new(bool), Service{}),
+	inject.KeyOf(new(int), Derived{}),
+	inject.KeyOf(new(int), Failing{}),
+	inject.KeyOf(new(string), Fallback{}),
+	inject.KeyOf(new(string), Lazy{}),
+	inject.KeyOf(new(string), Service{}),
+	inject.KeyOf(new(*inject.Injector), inject.Builtin{}),
+	inject.KeyOf(new(*int), Base{}),
+	inject.KeyOf(new(*url.URL), Endpoint{}),
+}
+
+// A chain of keys of DynamicInjector that are being resolved, from the requested key to the current one.
+type dynamicInjectorPath struct {
+	// The index of the getter of the key.
+	key int
+	// Whether the key was requested by calling a lazy argument of the parent's provider.
+	lazy   bool
+	parent *dynamicInjectorPath
+	// Set to 1 when the provider of the key returns.
+	finished int32
+}
+
+// Continue the path with the key, failing if resolving the key requires itself.
+func (self *dynamicInjectorPath) child(key int, lazy bool) (*dynamicInjectorPath, error) {
+	parent := self
+	if lazy && atomic.LoadInt32(&parent.finished) == 1 {
+		parent, lazy = nil, false
+	}
+	path := &dynamicInjectorPath{key: key, lazy: lazy, parent: parent}
+	for start := parent; start != nil; start = start.parent {
+		if start.key != key {
+			continue
+		}
+		steps := []inject.CycleStep{}
+		for element := path; element != start; element = element.parent {
+			steps = append([]inject.CycleStep{{Key: dynamicInjectorKeys[element.key], Lazy: element.lazy}}, steps...)
+		}
+		steps = append([]inject.CycleStep{{Key: dynamicInjectorKeys[key]}}, steps...)
+		keys := []inject.Key{}
+		for element := path; element != nil; element = element.parent {
+			keys = append([]inject.Key{dynamicInjectorKeys[element.key]}, keys...)
+		}
+		return nil, inject.CycleError{Steps: steps, Path: keys}
+	}
+	return path, nil
+}
+
+// Mark the provider of the path's key as returned.
+func (self *dynamicInjectorPath) finish() {
+	atomic.StoreInt32(&self.finished, 1)
+}
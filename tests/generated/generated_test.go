@@ -0,0 +1,145 @@
+package generated
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/monnoroch/go-inject"
+)
+
+type GeneratedInjectorTests struct {
+	suite.Suite
+}
+
+func (self *GeneratedInjectorTests) context() context.Context {
+	return context.WithValue(context.Background(), contextKey{}, "example.com")
+}
+
+func (self *GeneratedInjectorTests) TestSameValuesAsRuntimeInjector() {
+	injector, err := inject.InjectorOf(ValuesModule{Base: 1}, &ServiceModule{})
+	self.Require().Nil(err)
+	expected, err := injector.GetContext(self.context(), new(string), Service{})
+	self.Require().Nil(err)
+
+	value, err := NewInjector(ValuesModule{Base: 1}, &ServiceModule{}).Service(self.context())
+	self.Nil(err)
+	self.Equal(expected, value)
+	self.Equal("https://example.com/lazy", value)
+}
+
+func (self *GeneratedInjectorTests) TestCached() {
+	serviceModule := &ServiceModule{}
+	generated := NewInjector(ValuesModule{Base: 1}, serviceModule)
+	_, err := generated.Service(self.context())
+	self.Require().Nil(err)
+	_, err = generated.Failing(self.context())
+	self.Require().Nil(err)
+	self.Equal(1, serviceModule.Calls)
+}
+
+func (self *GeneratedInjectorTests) TestProviderError() {
+	_, err := NewInjector(ValuesModule{Base: 10}, &ServiceModule{}).Failing(self.context())
+	self.Equal(inject.ProviderFailedError{
+		Key:   inject.KeyOf(new(int), Failing{}),
+		Cause: ErrFailed,
+	}, err)
+	self.True(errors.Is(err, ErrFailed))
+}
+
+func (self *GeneratedInjectorTests) TestLazyError() {
+	_, err := NewInjector(ValuesModule{Base: 20}, &ServiceModule{}).Service(self.context())
+	self.Equal(inject.ProviderFailedError{
+		Key:   inject.KeyOf(new(string), Lazy{}),
+		Cause: ErrFailed,
+	}, err)
+}
+
+func (self *GeneratedInjectorTests) TestLazyReturnsError() {
+	for _, base := range []int{1, 20} {
+		injector, err := inject.InjectorOf(ValuesModule{Base: base}, &ServiceModule{})
+		self.Require().Nil(err)
+		expected, err := injector.GetContext(self.context(), new(string), Fallback{})
+		self.Require().Nil(err)
+
+		value, err := NewInjector(ValuesModule{Base: base}, &ServiceModule{}).Fallback(self.context())
+		self.Nil(err)
+		self.Equal(expected, value)
+	}
+
+	value, err := NewInjector(ValuesModule{Base: 20}, &ServiceModule{}).Fallback(self.context())
+	self.Nil(err)
+	self.Equal("fallback", value)
+}
+
+func (self *GeneratedInjectorTests) TestCanceled() {
+	ctx, cancel := context.WithCancel(self.context())
+	cancel()
+	serviceModule := &ServiceModule{}
+	generated := NewInjector(ValuesModule{Base: 1}, serviceModule)
+	_, err := generated.Service(ctx)
+	self.True(errors.Is(err, context.Canceled))
+	self.IsType(inject.CanceledError{}, err)
+
+	// Canceled values are not cached.
+	value, err := generated.Service(self.context())
+	self.Nil(err)
+	self.Equal("https://example.com/lazy", value)
+}
+
+func (self *GeneratedInjectorTests) TestDynamic() {
+	injector, err := inject.InjectorOf(ValuesModule{Base: 1})
+	self.Require().Nil(err)
+	generated := NewDynamicInjector(&ServiceModule{}, RuntimeModule{}, injector)
+
+	value, err := generated.Service(self.context())
+	self.Nil(err)
+	self.Equal("https://example.com/lazy", value)
+
+	hasInjector, err := generated.HasInjector(self.context())
+	self.Nil(err)
+	self.True(hasInjector)
+}
+
+func (self *GeneratedInjectorTests) TestDynamicMissingProvider() {
+	injector, err := inject.InjectorOf()
+	self.Require().Nil(err)
+	_, err = NewDynamicInjector(&ServiceModule{}, RuntimeModule{}, injector).Service(self.context())
+	self.IsType(inject.MissingProviderError{}, err)
+}
+
+func (self *GeneratedInjectorTests) TestLazyCycle() {
+	injector, err := inject.InjectorOf(CycleModule{})
+	self.Require().Nil(err)
+	generated := NewCycleInjector(CycleModule{})
+
+	_, expected := injector.Get(new(int), CycleRoot{})
+	self.Require().IsType(inject.CycleError{}, expected)
+	_, err = generated.CycleRoot(self.context())
+	self.Equal(expected, err)
+	self.Equal("Dependency cycle through lazy arguments: "+
+		"int/generated.Cycle ~> int/generated.CycleDependency -> int/generated.Cycle", err.Error())
+
+	// The provider returns the error of its lazy argument.
+	_, err = injector.Get(new(int), RecursiveRoot{})
+	expectedCycleErr := inject.CycleError{}
+	self.Require().True(errors.As(err, &expectedCycleErr))
+	_, err = generated.RecursiveRoot(self.context())
+	cycleErr := inject.CycleError{}
+	self.Require().True(errors.As(err, &cycleErr))
+	self.Equal(expectedCycleErr, cycleErr)
+}
+
+// Unlike the runtime injector, generated injectors allow calling lazy arguments after their
+// providers return, and such calls are not cycles.
+func (self *GeneratedInjectorTests) TestLazyCycleAfterProviding() {
+	value, err := NewCycleInjector(CycleModule{}).LaterRoot(self.context())
+	self.Nil(err)
+	self.Equal(1, value)
+}
+
+func TestGeneratedInjector(t *testing.T) {
+	suite.Run(t, new(GeneratedInjectorTests))
+}
@@ -0,0 +1,284 @@
+// Code generated by inject-gen. DO NOT EDIT.
+
+//go:build !inject_gen
+// +build !inject_gen
+
+package generated
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	"github.com/monnoroch/go-inject"
+)
+
+// Injector provides values of the modules ValuesModule, *ServiceModule without reflection.
+type Injector struct {
+	module0 ValuesModule
+	module1 *ServiceModule
+
+	// The cached value of *int/generated.Base.
+	cache0 struct {
+		sync.Mutex
+		done  bool
+		value *int
+		err   error
+	}
+
+	// The cached value of int/generated.Derived.
+	cache2 struct {
+		sync.Mutex
+		done  bool
+		value int
+		err   error
+	}
+}
+
+// Create an injector from the modules.
+func NewInjector(module0 ValuesModule, module1 *ServiceModule) *Injector {
+	return &Injector{module0: module0, module1: module1}
+}
+
+// Get the value of int/generated.Failing.
+func (self *Injector) Failing(ctx context.Context) (int, error) {
+	return self.get3(ctx, nil, false)
+}
+
+// Get the value of string/generated.Fallback.
+func (self *Injector) Fallback(ctx context.Context) (string, error) {
+	return self.get4(ctx, nil, false)
+}
+
+// Get the value of string/generated.Service.
+func (self *Injector) Service(ctx context.Context) (string, error) {
+	return self.get6(ctx, nil, false)
+}
+
+// Get the cached value of *int/generated.Base.
+func (self *Injector) get0(ctx context.Context, _ *injectorPath, _ bool) (value *int, err error) {
+	self.cache0.Lock()
+	defer self.cache0.Unlock()
+	if self.cache0.done {
+		return self.cache0.value, self.cache0.err
+	}
+	value, err = self.provide0(ctx)
+	// Values are not cached if the context was done before they were provided.
+	if _, canceled := err.(inject.CanceledError); !canceled {
+		self.cache0.value, self.cache0.err, self.cache0.done = value, err, true
+	}
+	return value, err
+}
+
+// Provide *int/generated.Base with ValuesModule.ProvideCachedBase.
+func (self *Injector) provide0(ctx context.Context) (value *int, err error) {
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(*int), Base{}), Cause: err}
+	}
+	value, _ = self.module0.ProvideCachedBase()
+	return value, nil
+}
+
+// Provide *url.URL/generated.Endpoint with ValuesModule.ProvideEndpoint.
+func (self *Injector) get1(ctx context.Context, _ *injectorPath, _ bool) (value *url.URL, err error) {
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(*url.URL), Endpoint{}), Cause: err}
+	}
+	value, _, providerErr := self.module0.ProvideEndpoint(ctx)
+	if providerErr != nil {
+		return value, inject.ProviderFailedError{Key: inject.KeyOf(new(*url.URL), Endpoint{}), Cause: providerErr}
+	}
+	return value, nil
+}
+
+// Get the cached value of int/generated.Derived.
+func (self *Injector) get2(ctx context.Context, parent *injectorPath, lazy bool) (value int, err error) {
+	// Fail on cycles before locking the cache, which this resolution can be holding.
+	path, err := parent.child(2, lazy)
+	if err != nil {
+		return value, err
+	}
+	self.cache2.Lock()
+	defer self.cache2.Unlock()
+	if self.cache2.done {
+		return self.cache2.value, self.cache2.err
+	}
+	value, err = self.provide2(ctx, path)
+	// Values are not cached if the context was done before they were provided.
+	if _, canceled := err.(inject.CanceledError); !canceled {
+		self.cache2.value, self.cache2.err, self.cache2.done = value, err, true
+	}
+	return value, err
+}
+
+// Provide int/generated.Derived with *ServiceModule.ProvideCachedDerived.
+func (self *Injector) provide2(ctx context.Context, path *injectorPath) (value int, err error) {
+	argument0, err := self.get0(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(int), Derived{}), Cause: err}
+	}
+	value, _ = self.module1.ProvideCachedDerived(argument0, Base{})
+	return value, nil
+}
+
+// Provide int/generated.Failing with *ServiceModule.ProvideFailing.
+func (self *Injector) get3(ctx context.Context, parent *injectorPath, lazy bool) (value int, err error) {
+	path, err := parent.child(3, lazy)
+	if err != nil {
+		return value, err
+	}
+	argument0, err := self.get2(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(int), Failing{}), Cause: err}
+	}
+	value, _, providerErr := self.module1.ProvideFailing(argument0, Derived{})
+	if providerErr != nil {
+		return value, inject.ProviderFailedError{Key: inject.KeyOf(new(int), Failing{}), Cause: providerErr}
+	}
+	return value, nil
+}
+
+// Provide string/generated.Fallback with *ServiceModule.ProvideFallback.
+func (self *Injector) get4(ctx context.Context, parent *injectorPath, lazy bool) (value string, err error) {
+	path, err := parent.child(4, lazy)
+	if err != nil {
+		return value, err
+	}
+	// Lazy arguments called after the provider returns start new resolutions.
+	defer path.finish()
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(string), Fallback{}), Cause: err}
+	}
+	value, _ = self.module1.ProvideFallback(func() (string, error) {
+		return self.get5(ctx, path, true)
+	}, Lazy{})
+	return value, nil
+}
+
+// Provide string/generated.Lazy with *ServiceModule.ProvideLazy.
+func (self *Injector) get5(ctx context.Context, parent *injectorPath, lazy bool) (value string, err error) {
+	path, err := parent.child(5, lazy)
+	if err != nil {
+		return value, err
+	}
+	argument0, err := self.get2(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(string), Lazy{}), Cause: err}
+	}
+	value, _, providerErr := self.module1.ProvideLazy(argument0, Derived{})
+	if providerErr != nil {
+		return value, inject.ProviderFailedError{Key: inject.KeyOf(new(string), Lazy{}), Cause: providerErr}
+	}
+	return value, nil
+}
+
+// Provide string/generated.Service with *ServiceModule.ProvideService.
+func (self *Injector) get6(ctx context.Context, parent *injectorPath, lazy bool) (value string, err error) {
+	path, err := parent.child(6, lazy)
+	if err != nil {
+		return value, err
+	}
+	// Lazy arguments called after the provider returns start new resolutions.
+	defer path.finish()
+	argument0, err := self.get2(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	argument1, err := self.get1(ctx, path, false)
+	if err != nil {
+		return value, err
+	}
+	// Resolving arguments can take long, the context could be done by now.
+	if err := ctx.Err(); err != nil {
+		return value, inject.CanceledError{Key: inject.KeyOf(new(string), Service{}), Cause: err}
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			lazyErr, ok := recovered.(injectorLazyError)
+			if !ok {
+				panic(recovered)
+			}
+			err = lazyErr.err
+		}
+	}()
+	value, _ = self.module1.ProvideService(argument0, Derived{}, argument1, Endpoint{}, func() string {
+		value, err := self.get5(ctx, path, true)
+		if err != nil {
+			panic(injectorLazyError{err: err})
+		}
+		return value
+	}, Lazy{})
+	return value, nil
+}
+
+// A panic of a lazy argument of Injector that failed to get its value.
+type injectorLazyError struct {
+	err error
+}
+
+// Keys of values of Injector by the indices of their getters.
+var injectorKeys = [...]inject.Key{
+	inject.KeyOf(new(*int), Base{}),
+	inject.KeyOf(new(*url.URL), Endpoint{}),
+	inject.KeyOf(new(int), Derived{}),
+	inject.KeyOf(new(int), Failing{}),
+	inject.KeyOf(new(string), Fallback{}),
+	inject.KeyOf(new(string), Lazy{}),
+	inject.KeyOf(new(string), Service{}),
+}
+
+// A chain of keys of Injector that are being resolved, from the requested key to the current one.
+type injectorPath struct {
+	// The index of the getter of the key.
+	key int
+	// Whether the key was requested by calling a lazy argument of the parent's provider.
+	lazy   bool
+	parent *injectorPath
+	// Set to 1 when the provider of the key returns.
+	finished int32
+}
+
+// Continue the path with the key, failing if resolving the key requires itself.
+func (self *injectorPath) child(key int, lazy bool) (*injectorPath, error) {
+	parent := self
+	if lazy && atomic.LoadInt32(&parent.finished) == 1 {
+		parent, lazy = nil, false
+	}
+	path := &injectorPath{key: key, lazy: lazy, parent: parent}
+	for start := parent; start != nil; start = start.parent {
+		if start.key != key {
+			continue
+		}
+		steps := []inject.CycleStep{}
+		for element := path; element != start; element = element.parent {
+			steps = append([]inject.CycleStep{{Key: injectorKeys[element.key], Lazy: element.lazy}}, steps...)
+		}
+		steps = append([]inject.CycleStep{{Key: injectorKeys[key]}}, steps...)
+		keys := []inject.Key{}
+		for element := path; element != nil; element = element.parent {
+			keys = append([]inject.Key{injectorKeys[element.key]}, keys...)
+		}
+		return nil, inject.CycleError{Steps: steps, Path: keys}
+	}
+	return path, nil
+}
+
+// Mark the provider of the path's key as returned.
+func (self *injectorPath) finish() {
+	atomic.StoreInt32(&self.finished, 1)
+}
@@ -0,0 +1,138 @@
+// generated tests injectors generated by inject-gen against the runtime injector.
+package generated
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/monnoroch/go-inject"
+)
+
+//go:generate go run ../../cmd/inject-gen -modules ValuesModule,*ServiceModule
+//go:generate go run ../../cmd/inject-gen -modules *ServiceModule,RuntimeModule -type DynamicInjector -output dynamic_inject_gen.go -dynamic
+//go:generate go run ../../cmd/inject-gen -modules CycleModule -type CycleInjector -output cycle_inject_gen.go
+
+type Base struct{}
+type Derived struct{}
+type Lazy struct{}
+type Fallback struct{}
+type Failing struct{}
+type Endpoint struct{}
+
+var ErrFailed = errors.New("failed")
+
+type contextKey struct{}
+
+type ValuesModule struct {
+	Base int
+}
+
+func (self ValuesModule) ProvideCachedBase() (*int, Base) {
+	value := self.Base
+	return &value, Base{}
+}
+
+func (self ValuesModule) ProvideEndpoint(ctx context.Context) (*url.URL, Endpoint, error) {
+	host, _ := ctx.Value(contextKey{}).(string)
+	return &url.URL{Scheme: "https", Host: host}, Endpoint{}, nil
+}
+
+type ServiceModule struct {
+	Calls int
+}
+
+func (self *ServiceModule) ProvideCachedDerived(base *int, _ Base) (int, Derived) {
+	self.Calls += 1
+	return *base + 1, Derived{}
+}
+
+func (self *ServiceModule) ProvideService(
+	derived int, _ Derived,
+	endpoint *url.URL, _ Endpoint,
+	lazy func() string, _ Lazy,
+) (string, Service) {
+	return endpoint.String() + "/" + lazy(), Service{}
+}
+
+func (self *ServiceModule) ProvideLazy(derived int, _ Derived) (string, Lazy, error) {
+	if derived > 20 {
+		return "", Lazy{}, ErrFailed
+	}
+	return "lazy", Lazy{}, nil
+}
+
+func (self *ServiceModule) ProvideFallback(lazy func() (string, error), _ Lazy) (string, Fallback) {
+	value, err := lazy()
+	if err != nil {
+		return "fallback", Fallback{}
+	}
+	return value, Fallback{}
+}
+
+func (self *ServiceModule) ProvideFailing(derived int, _ Derived) (int, Failing, error) {
+	if derived > 10 {
+		return 0, Failing{}, ErrFailed
+	}
+	return derived, Failing{}, nil
+}
+
+type RuntimeModule struct{}
+
+func (self RuntimeModule) ProvideHasInjector(injector *inject.Injector, _ inject.Builtin) (bool, Service) {
+	return injector != nil, Service{}
+}
+
+type Service struct{}
+
+type Cycle struct{}
+type CycleDependency struct{}
+type CycleRoot struct{}
+type Recursive struct{}
+type RecursiveRoot struct{}
+type Later struct{}
+type LaterDependency struct{}
+type LaterRoot struct{}
+
+// A module with dependency cycles through lazy arguments.
+type CycleModule struct{}
+
+// A cached value that requires itself when it calls its lazy argument.
+func (self CycleModule) ProvideCachedCycle(dependency func() int, _ CycleDependency) (int, Cycle) {
+	return dependency(), Cycle{}
+}
+
+func (self CycleModule) ProvideCycleDependency(value int, _ Cycle) (int, CycleDependency) {
+	return value, CycleDependency{}
+}
+
+func (self CycleModule) ProvideCycleRoot(value int, _ Cycle) (int, CycleRoot) {
+	return value, CycleRoot{}
+}
+
+// A not cached value that requires itself when it calls its lazy argument.
+func (self CycleModule) ProvideRecursive(next func() (int, error), _ Recursive) (int, Recursive, error) {
+	value, err := next()
+	return value + 1, Recursive{}, err
+}
+
+func (self CycleModule) ProvideRecursiveRoot(value int, _ Recursive) (int, RecursiveRoot) {
+	return value, RecursiveRoot{}
+}
+
+// A value that keeps its lazy argument, which requires the value, to call it after it is provided.
+type Deferred struct {
+	Get func() int
+}
+
+func (self CycleModule) ProvideCachedLater(dependency func() int, _ LaterDependency) (Deferred, Later) {
+	return Deferred{Get: dependency}, Later{}
+}
+
+func (self CycleModule) ProvideLaterDependency(_ Deferred, _ Later) (int, LaterDependency) {
+	return 1, LaterDependency{}
+}
+
+func (self CycleModule) ProvideLaterRoot(later Deferred, _ Later) (int, LaterRoot) {
+	return later.Get(), LaterRoot{}
+}
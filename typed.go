@@ -0,0 +1,101 @@
+package inject
+
+import (
+	"context"
+	"reflect"
+)
+
+// A key of values of type T with annotation type A.
+// Unlike `Key`, the types of the values that it gets are known at compile time.
+type TypedKey[T any, A Annotation] struct{}
+
+// Get the untyped key.
+func (self TypedKey[T, A]) Key() Key {
+	return KeyOf(new(T), *new(A))
+}
+
+func (self TypedKey[T, A]) String() string {
+	return self.Key().String()
+}
+
+// Get the value of the key from the injector, see `Get`.
+func (self TypedKey[T, A]) Get(injector *Injector) (T, error) {
+	return Get[T, A](injector)
+}
+
+// Get the value of the key from the injector, panic if there was an error.
+func (self TypedKey[T, A]) MustGet(injector *Injector) T {
+	return MustGet[T, A](injector)
+}
+
+// Get the value of the key from the injector with the context, see `GetContext`.
+func (self TypedKey[T, A]) GetContext(ctx context.Context, injector *Injector) (T, error) {
+	return GetContext[T, A](ctx, injector)
+}
+
+// Get the value of type T with annotation type A from the injector.
+// Same as `Injector.Get`, but the value has the requested type.
+func Get[T any, A Annotation](injector *Injector) (T, error) {
+	return typedValue[T](injector.Get(new(T), *new(A)))
+}
+
+// Get the value of type T with annotation type A from the injector, panic if there was an error.
+func MustGet[T any, A Annotation](injector *Injector) T {
+	value, err := Get[T, A](injector)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// Get the value of type T with annotation type A from the injector, passing the context to
+// providers that take it.
+// Same as `Injector.GetContext`, but the value has the requested type.
+func GetContext[T any, A Annotation](ctx context.Context, injector *Injector) (T, error) {
+	return typedValue[T](injector.GetContext(ctx, new(T), *new(A)))
+}
+
+// Convert the value got from the injector to its type.
+// Nil values of interface types are provided as nil interfaces, which are zero values.
+func typedValue[T any](value interface{}, err error) (T, error) {
+	var result T
+	if err != nil || value == nil {
+		return result, err
+	}
+	return value.(T), nil
+}
+
+// Create a provider of the value of type T with annotation type A.
+func ValueProvider[T any, A Annotation](value T) Provider {
+	return ProviderFunc[T, A](func(context.Context, *Injector) (T, error) {
+		return value, nil
+	})
+}
+
+// Create a provider of values of type T with annotation type A from a function.
+// The function gets the context of the resolution and an injector that provides dependencies of
+// the value, for example with `Get`, so dependency cycles are detected.
+// Such dependencies are not known in advance, so they are not checked by `ValidateGraph` and
+// not reported by introspection.
+func ProviderFunc[T any, A Annotation](function func(context.Context, *Injector) (T, error)) Provider {
+	valueType := reflect.TypeOf(new(T)).Elem()
+	annotationType := reflect.TypeOf(new(A)).Elem()
+	functionType := reflect.FuncOf(
+		[]reflect.Type{globalContextType, injectorKey.valueType, injectorKey.annotationType},
+		[]reflect.Type{valueType, annotationType, globalErrorType},
+		false,
+	)
+	provider := NewProvider(reflect.MakeFunc(functionType, func(arguments []reflect.Value) []reflect.Value {
+		value, err := function(
+			arguments[0].Interface().(context.Context),
+			arguments[1].Interface().(*Injector),
+		)
+		errValue := reflect.Zero(globalErrorType)
+		if err != nil {
+			errValue = reflect.ValueOf(&err).Elem()
+		}
+		return []reflect.Value{reflect.ValueOf(&value).Elem(), reflect.Zero(annotationType), errValue}
+	}))
+	provider.source = functionPointer(reflect.ValueOf(function))
+	return provider
+}
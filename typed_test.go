@@ -0,0 +1,144 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TypedTests struct {
+	suite.Suite
+}
+
+type typedTestModule struct{}
+
+func (self typedTestModule) ProvideValue() (int, Annotation1) {
+	return testValue, Annotation1{}
+}
+
+func (self typedTestModule) ProvideStringer() (fmt.Stringer, Annotation1) {
+	return nil, Annotation1{}
+}
+
+func (self typedTestModule) ProvideError() (int, Annotation2, error) {
+	return 0, Annotation2{}, testError
+}
+
+func (self *TypedTests) TestTypedKey() {
+	self.Equal(testKey(Annotation1{}), TypedKey[int, Annotation1]{}.Key())
+	self.Equal("int/inject.Annotation1", TypedKey[int, Annotation1]{}.String())
+}
+
+func (self *TypedTests) TestGet() {
+	injector, err := InjectorOf(typedTestModule{})
+	self.Require().Nil(err)
+
+	value, err := Get[int, Annotation1](injector)
+	self.Nil(err)
+	self.Equal(testValue, value)
+	self.Equal(testValue, MustGet[int, Annotation1](injector))
+
+	value, err = TypedKey[int, Annotation1]{}.Get(injector)
+	self.Nil(err)
+	self.Equal(testValue, value)
+	self.Equal(testValue, TypedKey[int, Annotation1]{}.MustGet(injector))
+}
+
+func (self *TypedTests) TestGetNilInterface() {
+	injector, err := InjectorOf(typedTestModule{})
+	self.Require().Nil(err)
+	value, err := Get[fmt.Stringer, Annotation1](injector)
+	self.Nil(err)
+	self.Nil(value)
+}
+
+func (self *TypedTests) TestGetError() {
+	injector, err := InjectorOf(typedTestModule{})
+	self.Require().Nil(err)
+
+	value, err := Get[int, Annotation2](injector)
+	self.Equal(0, value)
+	self.True(errors.Is(err, testError))
+	self.Panics(func() {
+		MustGet[int, Annotation2](injector)
+	})
+
+	_, err = Get[string, Annotation1](injector)
+	self.IsType(MissingProviderError{}, err)
+}
+
+func (self *TypedTests) TestGetContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	injector, err := InjectorOf(typedTestModule{})
+	self.Require().Nil(err)
+	_, err = GetContext[int, Annotation1](ctx, injector)
+	self.True(errors.Is(err, context.Canceled))
+	_, err = TypedKey[int, Annotation1]{}.GetContext(ctx, injector)
+	self.True(errors.Is(err, context.Canceled))
+}
+
+type typedTestProvidersModule struct{}
+
+func (self typedTestProvidersModule) Providers() ([]Provider, error) {
+	return []Provider{
+		ValueProvider[int, Annotation1](testValue),
+		ProviderFunc[string, Annotation2](func(ctx context.Context, injector *Injector) (string, error) {
+			value, err := Get[int, Annotation1](injector)
+			return fmt.Sprint(value), err
+		}),
+		ProviderFunc[string, Annotation3](func(ctx context.Context, injector *Injector) (string, error) {
+			return "", testError
+		}).Cached(true),
+	}, nil
+}
+
+func (self *TypedTests) TestProviders() {
+	injector, err := InjectorOf(typedTestProvidersModule{})
+	self.Require().Nil(err)
+
+	self.Equal(testValue, MustGet[int, Annotation1](injector))
+	self.Equal(fmt.Sprint(testValue), MustGet[string, Annotation2](injector))
+	_, err = Get[string, Annotation3](injector)
+	self.Equal(ProviderFailedError{
+		Key:   KeyOf(new(string), Annotation3{}),
+		Path:  []Key{KeyOf(new(string), Annotation3{})},
+		Cause: testError,
+	}, err)
+	self.True(injector.IsCached(KeyOf(new(string), Annotation3{})))
+}
+
+type typedTestCycleModule struct{}
+
+func (self typedTestCycleModule) Providers() ([]Provider, error) {
+	return []Provider{
+		ProviderFunc[int, Annotation1](func(ctx context.Context, injector *Injector) (int, error) {
+			return Get[int, Annotation1](injector)
+		}),
+	}, nil
+}
+
+func (self *TypedTests) TestProviderFuncCycle() {
+	injector, err := InjectorOf(typedTestCycleModule{})
+	self.Require().Nil(err)
+	_, err = Get[int, Annotation1](injector)
+	// The function returns the cycle error as its own error.
+	var cycleErr CycleError
+	self.True(errors.As(err, &cycleErr))
+}
+
+func (self *TypedTests) TestProviderFuncLocation() {
+	provider := ProviderFunc[int, Annotation1](func(ctx context.Context, injector *Injector) (int, error) {
+		return 0, nil
+	})
+	file, _ := provider.Location()
+	self.True(strings.HasSuffix(file, "typed_test.go"))
+}
+
+func TestTyped(t *testing.T) {
+	suite.Run(t, new(TypedTests))
+}